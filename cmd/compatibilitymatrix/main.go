@@ -0,0 +1,75 @@
+// Command compatibilitymatrix merges the results that runs of the integration
+// suite against different builders and targets saved to build/compatibility,
+// and renders them into one compatibility matrix.
+//
+// Usage:
+//
+//	go run ./cmd/compatibilitymatrix [--results build/compatibility] [--output build]
+//
+// The matrix is written to compatibility-matrix.md and
+// compatibility-matrix.json in the output directory. It lists every builder in
+// integration.json and every target in package.toml, and reports the
+// combinations without results as not tested.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/nodejs/integration/compatibility"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compatibilitymatrix", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	resultsDir := flags.String("results", filepath.Join("build", "compatibility"), "directory holding the results saved by the integration suite")
+	integrationJSONPath := flags.String("integration-json", "integration.json", "path to the integration.json that lists the builders")
+	packagePath := flags.String("package-toml", "package.toml", "path to the package.toml that lists the targets")
+	outputDir := flags.String("output", "build", "directory to write the matrix to")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	results, err := compatibility.Load(*resultsDir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if len(results) == 0 {
+		fmt.Fprintf(stderr, "no results found in %s\n", *resultsDir)
+		return 1
+	}
+
+	builders, err := compatibility.Builders(*integrationJSONPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	targets, err := compatibility.Targets(*packagePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	err = compatibility.NewMatrix(builders, targets, results).Write(*outputDir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "wrote %s\n", filepath.Join(*outputDir, "compatibility-matrix.md"))
+
+	return 0
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/nodejs/integration/compatibility"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompatibilityMatrix(t *testing.T) {
+	suite := spec.New("compatibilitymatrix", spec.Report(report.Terminal{}))
+	suite("Run", testRun)
+	suite.Run(t)
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		resultsDir string
+		outputDir  string
+		args       []string
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		resultsDir = filepath.Join(dir, "results")
+		outputDir = filepath.Join(dir, "build")
+
+		integrationJSONPath := filepath.Join(dir, "integration.json")
+		Expect(os.WriteFile(integrationJSONPath, []byte(`{"builders": ["index.docker.io/some-org/some-builder:latest", "index.docker.io/some-org/other-builder:latest"]}`), 0644)).To(Succeed())
+
+		packagePath := filepath.Join(dir, "package.toml")
+		Expect(os.WriteFile(packagePath, []byte(`
+[[targets]]
+  os = "linux"
+  arch = "amd64"
+
+[[targets]]
+  os = "linux"
+  arch = "arm64"
+`), 0644)).To(Succeed())
+
+		args = []string{"--results", resultsDir, "--integration-json", integrationJSONPath, "--package-toml", packagePath, "--output", outputDir}
+
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+	})
+
+	it("merges the results of every run into one matrix", func() {
+		amd64 := compatibility.NewRecorder("index.docker.io/some-org/some-builder:latest", "linux/amd64")
+		amd64.Record([]string{"NPM", "when building an app", "builds"}, compatibility.Passed)
+		Expect(amd64.Save(resultsDir)).To(Succeed())
+
+		arm64 := compatibility.NewRecorder("index.docker.io/some-org/some-builder:latest", "linux/arm64")
+		arm64.Record([]string{"NPM", "when building an app", "builds"}, compatibility.Failed)
+		Expect(arm64.Save(resultsDir)).To(Succeed())
+
+		code := run(args, stdout, stderr)
+		Expect(code).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal("wrote " + filepath.Join(outputDir, "compatibility-matrix.md") + "\n"))
+
+		content, err := os.ReadFile(filepath.Join(outputDir, "compatibility-matrix.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("| `some-org/some-builder` | passed (1) | failed (1 of 1) |\n"))
+		Expect(string(content)).To(ContainSubstring("| `some-org/other-builder` | not tested | not tested |\n"))
+	})
+
+	context("when there are no results", func() {
+		it("fails", func() {
+			code := run(args, stdout, stderr)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(Equal("no results found in " + resultsDir + "\n"))
+		})
+	})
+}
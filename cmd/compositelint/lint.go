@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// Problem describes a single inconsistency found between buildpack.toml and
+// package.toml.
+type Problem struct {
+	File    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// Lint parses the buildpack.toml and package.toml at the given paths and
+// reports every inconsistency between them.
+func Lint(buildpackPath, packagePath string) ([]Problem, error) {
+	buildpack, err := composite.DecodeBuildpack(buildpackPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := composite.DecodePackage(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+
+	dependencies := map[string][]composite.Dependency{}
+	seenURIs := map[string]bool{}
+	for _, d := range pkg.Dependencies {
+		if seenURIs[d.URI] {
+			problems = append(problems, Problem{packagePath, fmt.Sprintf("dependency %q is listed more than once", d.URI)})
+			continue
+		}
+		seenURIs[d.URI] = true
+
+		dep, err := composite.ParseDependencyURI(d.URI)
+		if err != nil {
+			problems = append(problems, Problem{packagePath, err.Error()})
+			continue
+		}
+
+		dependencies[dep.ID] = append(dependencies[dep.ID], dep)
+	}
+
+	// versions records the order groups that use each id@version, so that
+	// dependencies that no group uses can be reported afterwards.
+	versions := map[string]map[string][]int{}
+
+	for i, order := range buildpack.Order {
+		group := i + 1
+
+		seenIDs := map[string]bool{}
+		for _, member := range order.Group {
+			if seenIDs[member.ID] {
+				problems = append(problems, Problem{buildpackPath, fmt.Sprintf("order group %d lists %s more than once", group, member.ID)})
+				continue
+			}
+			seenIDs[member.ID] = true
+
+			if versions[member.ID] == nil {
+				versions[member.ID] = map[string][]int{}
+			}
+			versions[member.ID][member.Version] = append(versions[member.ID][member.Version], group)
+
+			deps, ok := dependencies[member.ID]
+			if !ok {
+				problems = append(problems, Problem{buildpackPath, fmt.Sprintf("order group %d: %s@%s has no matching dependency in %s", group, member.ID, member.Version, packagePath)})
+				continue
+			}
+
+			if !slices.ContainsFunc(deps, func(d composite.Dependency) bool { return d.Version == member.Version }) {
+				var available []string
+				for _, d := range deps {
+					available = append(available, d.Version)
+				}
+
+				problems = append(problems, Problem{buildpackPath, fmt.Sprintf("order group %d: %s@%s does not match the version in %s (%s)", group, member.ID, member.Version, packagePath, strings.Join(available, ", "))})
+			}
+		}
+	}
+
+	for _, id := range composite.SortedKeys(versions) {
+		if len(versions[id]) < 2 {
+			continue
+		}
+
+		var details []string
+		for _, version := range composite.SortedKeys(versions[id]) {
+			var groups []string
+			for _, group := range versions[id][version] {
+				groups = append(groups, fmt.Sprint(group))
+			}
+			details = append(details, fmt.Sprintf("%s in order group(s) %s", version, strings.Join(groups, ", ")))
+		}
+
+		problems = append(problems, Problem{buildpackPath, fmt.Sprintf("%s is used with different versions: %s", id, strings.Join(details, "; "))})
+	}
+
+	for _, id := range composite.SortedKeys(dependencies) {
+		for _, dep := range dependencies[id] {
+			if _, ok := versions[id][dep.Version]; !ok {
+				problems = append(problems, Problem{packagePath, fmt.Sprintf("dependency %q is not used by any order group in %s", dep.URI, buildpackPath)})
+			}
+		}
+	}
+
+	problems = append(problems, shadowedGroups(buildpackPath, buildpack)...)
+
+	return problems, nil
+}
+
+// shadowedGroups reports order groups that can never be selected. Groups are
+// tried in order and the first one whose non-optional members all pass
+// detection wins, so a group is unreachable when an earlier group requires
+// only a subset of its own non-optional members.
+func shadowedGroups(path string, buildpack composite.Buildpack) []Problem {
+	var required [][]string
+	for _, order := range buildpack.Order {
+		var ids []string
+		for _, member := range order.Group {
+			if !member.Optional {
+				ids = append(ids, member.ID)
+			}
+		}
+		required = append(required, ids)
+	}
+
+	var problems []Problem
+	for later := range required {
+		for earlier := 0; earlier < later; earlier++ {
+			if isSubset(required[earlier], required[later]) {
+				problems = append(problems, Problem{path, fmt.Sprintf("order group %d can never be selected because the non-optional members of order group %d (%s) are a subset of its own (%s)",
+					later+1, earlier+1, strings.Join(required[earlier], ", "), strings.Join(required[later], ", "))})
+				break
+			}
+		}
+	}
+
+	return problems
+}
+
+func isSubset(subset, set []string) bool {
+	for _, id := range subset {
+		if !slices.Contains(set, id) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompositeLint(t *testing.T) {
+	suite := spec.New("compositelint", spec.Report(report.Terminal{}))
+	suite("Lint", testLint)
+	suite("Run", testRun)
+	suite.Run(t)
+}
+
+func testLint(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buildpackPath string
+		packagePath   string
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		buildpackPath = filepath.Join(dir, "buildpack.toml")
+		packagePath = filepath.Join(dir, "package.toml")
+	})
+
+	write := func(buildpack, pkg string) {
+		Expect(os.WriteFile(buildpackPath, []byte(buildpack), 0644)).To(Succeed())
+		Expect(os.WriteFile(packagePath, []byte(pkg), 0644)).To(Succeed())
+	}
+
+	messages := func(problems []Problem) []string {
+		var messages []string
+		for _, problem := range problems {
+			messages = append(messages, problem.Message)
+		}
+		return messages
+	}
+
+	context("when the files are consistent", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/npm-install"
+    version = "2.0.0"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-start"
+    version = "3.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/npm-install:2.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-start:3.0.0"
+`)
+		})
+
+		it("reports no problems", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(BeEmpty())
+		})
+	})
+
+	context("when a group member version does not match its dependency", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.1"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`)
+		})
+
+		it("reports the mismatch and the dependency that is left unused", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				ContainSubstring("order group 1: paketo-buildpacks/node-engine@1.0.1 does not match the version in %s (1.0.0)", packagePath),
+				ContainSubstring(`dependency "docker://docker.io/paketobuildpacks/node-engine:1.0.0" is not used by any order group`),
+			))
+			Expect(problems[0].File).To(Equal(buildpackPath))
+			Expect(problems[1].File).To(Equal(packagePath))
+		})
+	})
+
+	context("when a group member has no dependency", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/datadog"
+    optional = true
+    version = "5.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`)
+		})
+
+		it("reports the missing dependency", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				ContainSubstring("order group 1: paketo-buildpacks/datadog@5.0.0 has no matching dependency"),
+			))
+		})
+	})
+
+	context("when a dependency is not used by any group", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-module-bom:0.5.7"
+`)
+		})
+
+		it("reports the orphan dependency", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				ContainSubstring(`dependency "docker://docker.io/paketobuildpacks/node-module-bom:0.5.7" is not used by any order group`),
+			))
+		})
+	})
+
+	context("when a dependency is listed more than once", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`)
+		})
+
+		it("reports the duplicate dependency", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				ContainSubstring(`dependency "docker://docker.io/paketobuildpacks/node-engine:1.0.0" is listed more than once`),
+			))
+		})
+	})
+
+	context("when a group lists the same buildpack more than once", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`)
+		})
+
+		it("reports the duplicate entry", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				"order group 1 lists paketo-buildpacks/node-engine more than once",
+			))
+		})
+	})
+
+	context("when groups use different versions of the same buildpack", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/yarn"
+    version = "1.0.0"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.1.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-start"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.1.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/yarn:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-start:1.0.0"
+`)
+		})
+
+		it("reports the groups that disagree", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				"paketo-buildpacks/node-engine is used with different versions: 1.0.0 in order group(s) 1; 1.1.0 in order group(s) 2",
+			))
+		})
+	})
+
+	context("when the non-optional members of a group are covered by an earlier group", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-start"
+    optional = true
+    version = "1.0.0"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/npm-install"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-start:1.0.0"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/npm-install:1.0.0"
+`)
+		})
+
+		it("reports the group that can never be selected", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				"order group 2 can never be selected because the non-optional members of order group 1 (paketo-buildpacks/node-engine) are a subset of its own (paketo-buildpacks/node-engine, paketo-buildpacks/npm-install)",
+			))
+		})
+	})
+
+	context("when a dependency is not an image reference with a version", func() {
+		it.Before(func() {
+			write(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`, `
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+
+[[dependencies]]
+  uri = "build/some-buildpack.tgz"
+
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-start"
+`)
+		})
+
+		it("reports the unsupported dependencies", func() {
+			problems, err := Lint(buildpackPath, packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages(problems)).To(ConsistOf(
+				`dependency "build/some-buildpack.tgz" is not a docker:// image reference`,
+				`dependency "docker://docker.io/paketobuildpacks/node-start" does not specify a version tag`,
+			))
+		})
+	})
+
+	context("when linting the files of this repository", func() {
+		it("reports no problems", func() {
+			problems, err := Lint(filepath.Join("..", "..", "buildpack.toml"), filepath.Join("..", "..", "package.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(problems).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when buildpack.toml cannot be parsed", func() {
+			it.Before(func() {
+				write("%%%", "")
+			})
+
+			it("returns an error", func() {
+				_, err := Lint(buildpackPath, packagePath)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse %s", buildpackPath)))
+			})
+		})
+
+		context("when package.toml cannot be parsed", func() {
+			it.Before(func() {
+				write("", "%%%")
+			})
+
+			it("returns an error", func() {
+				_, err := Lint(buildpackPath, packagePath)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse %s", packagePath)))
+			})
+		})
+	})
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+		dir    string
+	)
+
+	it.Before(func() {
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+		dir = t.TempDir()
+
+		Expect(os.WriteFile(filepath.Join(dir, "buildpack.toml"), []byte(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`), 0644)).To(Succeed())
+	})
+
+	context("when there are no problems", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(dir, "package.toml"), []byte(`
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`), 0644)).To(Succeed())
+		})
+
+		it("exits successfully", func() {
+			code := run([]string{
+				"--buildpack-toml", filepath.Join(dir, "buildpack.toml"),
+				"--package-toml", filepath.Join(dir, "package.toml"),
+			}, stdout, stderr)
+			Expect(code).To(Equal(0))
+			Expect(stdout.String()).To(BeEmpty())
+		})
+	})
+
+	context("when there are problems", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(dir, "package.toml"), []byte(`
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:0.9.0"
+`), 0644)).To(Succeed())
+		})
+
+		it("prints each problem and exits with a failure", func() {
+			code := run([]string{
+				"--buildpack-toml", filepath.Join(dir, "buildpack.toml"),
+				"--package-toml", filepath.Join(dir, "package.toml"),
+			}, stdout, stderr)
+			Expect(code).To(Equal(1))
+			Expect(stdout.String()).To(ContainSubstring("buildpack.toml: order group 1: paketo-buildpacks/node-engine@1.0.0 does not match the version"))
+			Expect(stdout.String()).To(ContainSubstring("package.toml: dependency \"docker://docker.io/paketobuildpacks/node-engine:0.9.0\" is not used by any order group"))
+			Expect(stderr.String()).To(ContainSubstring("found 2 problem(s)"))
+		})
+	})
+
+	context("when the files cannot be read", func() {
+		it("exits with a usage error", func() {
+			code := run([]string{"--buildpack-toml", filepath.Join(dir, "missing.toml")}, stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("failed to parse"))
+		})
+	})
+}
@@ -0,0 +1,51 @@
+// Command compositelint checks that the order groups in buildpack.toml and the
+// dependencies in package.toml describe the same set of component buildpacks.
+//
+// Usage:
+//
+//	go run ./cmd/compositelint [--buildpack-toml buildpack.toml] [--package-toml package.toml]
+//
+// Every problem found is printed on its own line and the command exits with a
+// non-zero status if there are any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compositelint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	buildpackPath := flags.String("buildpack-toml", "buildpack.toml", "path to the buildpack.toml of the composite buildpack")
+	packagePath := flags.String("package-toml", "package.toml", "path to the package.toml of the composite buildpack")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	problems, err := Lint(*buildpackPath, *packagePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(stdout, problem)
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintf(stderr, "found %d problem(s)\n", len(problems))
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// detector is the stub detect logic for a component buildpack. It reports
+// whether the buildpack would pass detection for the app and why.
+type detector func(app *detectContext) (bool, string)
+
+// detectors maps every component buildpack id in buildpack.toml onto stub
+// detect logic that approximates the detection of the real buildpack. It only
+// looks at the files of the app, the build environment and the service
+// bindings, so that the outcome can be predicted without running a build.
+var detectors = map[string]detector{
+	"paketo-buildpacks/ca-certificates": func(app *detectContext) (bool, string) {
+		if app.hasBinding("ca-certificates") {
+			return true, "found a ca-certificates service binding"
+		}
+		return false, "no ca-certificates service binding found"
+	},
+
+	"paketo-buildpacks/watchexec": func(app *detectContext) (bool, string) {
+		return app.enabled("BP_LIVE_RELOAD_ENABLED")
+	},
+
+	"paketo-buildpacks/tini": func(app *detectContext) (bool, string) {
+		return false, "no other buildpack requires tini"
+	},
+
+	"paketo-buildpacks/bun": func(app *detectContext) (bool, string) {
+		return app.anyFile("bun.lock", "bun.lockb")
+	},
+
+	"paketo-buildpacks/bun-install": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+		return app.anyFile("bun.lock", "bun.lockb")
+	},
+
+	"paketo-buildpacks/bun-start": func(app *detectContext) (bool, string) {
+		if app.script("start") {
+			return true, "package.json defines a start script"
+		}
+		return app.launchpoint()
+	},
+
+	"paketo-buildpacks/deno": func(app *detectContext) (bool, string) {
+		return app.anyFile("deno.json", "deno.jsonc")
+	},
+
+	"paketo-buildpacks/deno-install": func(app *detectContext) (bool, string) {
+		return app.anyFile("deno.lock")
+	},
+
+	"paketo-buildpacks/deno-start": func(app *detectContext) (bool, string) {
+		for _, name := range []string{"deno.json", "deno.jsonc"} {
+			content, err := os.ReadFile(filepath.Join(app.projectDir, name))
+			if err != nil {
+				continue
+			}
+
+			var config struct {
+				Tasks map[string]string `json:"tasks"`
+			}
+			if err := json.Unmarshal(content, &config); err != nil {
+				return false, fmt.Sprintf("failed to parse %s: %s", name, err)
+			}
+
+			if _, ok := config.Tasks["start"]; ok {
+				return true, fmt.Sprintf("%s defines a start task", name)
+			}
+			return false, fmt.Sprintf("%s does not define a start task", name)
+		}
+		return false, "no deno.json or deno.jsonc file found"
+	},
+
+	"paketo-buildpacks/cpython": func(app *detectContext) (bool, string) {
+		if ok, reason := app.nativeAddons(); ok {
+			return true, fmt.Sprintf("provides Python for node-gyp: %s", reason)
+		}
+		return false, "no native addons need to be rebuilt"
+	},
+
+	"paketo-buildpacks/node-engine": func(app *detectContext) (bool, string) {
+		return true, "provides Node.js"
+	},
+
+	"paketo-buildpacks/yarn": func(app *detectContext) (bool, string) {
+		return app.lockfile("yarn.lock")
+	},
+
+	"paketo-buildpacks/git-cli": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+
+		for _, name := range composite.SortedKeys(app.pkg.Dependencies) {
+			version := app.pkg.Dependencies[name]
+			if strings.HasPrefix(version, "git") || strings.HasPrefix(version, "github:") || strings.Contains(version, ".git") {
+				return true, fmt.Sprintf("dependency %q is installed from git (%s)", name, version)
+			}
+		}
+		return false, "no dependencies are installed from git"
+	},
+
+	"paketo-buildpacks/corepack": func(app *detectContext) (bool, string) {
+		if app.pkg == nil || app.pkg.PackageManager == "" {
+			return false, "package.json does not declare a packageManager"
+		}
+
+		manager, _, _ := strings.Cut(app.pkg.PackageManager, "@")
+		for lockfile, owner := range map[string]string{"yarn.lock": "yarn", "pnpm-lock.yaml": "pnpm", "package-lock.json": "npm"} {
+			if ok, _ := app.lockfile(lockfile); ok && owner != manager {
+				return true, fmt.Sprintf("package.json declares packageManager %s, which does not match %s; the build will fail", app.pkg.PackageManager, lockfile)
+			}
+		}
+		return true, fmt.Sprintf("package.json declares packageManager %s", app.pkg.PackageManager)
+	},
+
+	"paketo-buildpacks/yarn-install": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+		return app.lockfile("yarn.lock")
+	},
+
+	"paketo-buildpacks/npm-install": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+		return true, "found package.json"
+	},
+
+	"paketo-buildpacks/pnpm": func(app *detectContext) (bool, string) {
+		return app.lockfile("pnpm-lock.yaml")
+	},
+
+	"paketo-buildpacks/pnpm-install": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+		return app.lockfile("pnpm-lock.yaml")
+	},
+
+	"paketo-buildpacks/node-gyp": func(app *detectContext) (bool, string) {
+		return app.nativeAddons()
+	},
+
+	"paketo-buildpacks/node-module-bom": func(app *detectContext) (bool, string) {
+		if app.pkg == nil {
+			return false, "no package.json found"
+		}
+		return true, "generates an SBOM for the installed node modules"
+	},
+
+	"paketo-buildpacks/node-run-script": func(app *detectContext) (bool, string) {
+		value, ok := app.env["BP_NODE_RUN_SCRIPTS"]
+		if !ok || value == "" {
+			return false, "BP_NODE_RUN_SCRIPTS is not set"
+		}
+
+		for _, script := range strings.Split(value, ",") {
+			script = strings.TrimSpace(script)
+			if !app.script(script) {
+				return false, fmt.Sprintf("package.json does not define the %q script from BP_NODE_RUN_SCRIPTS", script)
+			}
+		}
+		return true, fmt.Sprintf("runs the scripts in BP_NODE_RUN_SCRIPTS (%s)", value)
+	},
+
+	"paketo-buildpacks/nextjs": func(app *detectContext) (bool, string) {
+		if app.pkg == nil || app.pkg.Dependencies["next"] == "" {
+			return false, "the app does not depend on next"
+		}
+
+		if app.env["BP_NEXTJS_STANDALONE_ENABLED"] == "false" {
+			return false, "BP_NEXTJS_STANDALONE_ENABLED is \"false\""
+		}
+
+		for _, name := range []string{"next.config.js", "next.config.mjs", "next.config.ts"} {
+			content, err := os.ReadFile(filepath.Join(app.projectDir, name))
+			if err == nil && strings.Contains(string(content), "standalone") {
+				return true, fmt.Sprintf("%s configures standalone output", name)
+			}
+		}
+		return false, "the Next.js config does not configure standalone output"
+	},
+
+	"paketo-buildpacks/typescript": func(app *detectContext) (bool, string) {
+		return app.anyFile("tsconfig.json")
+	},
+
+	"paketo-buildpacks/chromium": func(app *detectContext) (bool, string) {
+		for _, name := range []string{"puppeteer", "puppeteer-core", "playwright", "playwright-core"} {
+			if app.dependsOn(name) {
+				return true, fmt.Sprintf("the app depends on %s", name)
+			}
+		}
+		return false, "the app does not depend on Puppeteer or Playwright"
+	},
+
+	"paketo-buildpacks/node-start": func(app *detectContext) (bool, string) {
+		if app.passed["paketo-buildpacks/nextjs"] {
+			return false, "the start command is provided by paketo-buildpacks/nextjs"
+		}
+
+		ok, reason := app.launchpoint()
+		if ok {
+			return true, reason
+		}
+
+		if ok, _ := app.anyFile("tsconfig.json"); ok {
+			return true, "launches the entry point compiled from tsconfig.json"
+		}
+		return false, reason
+	},
+
+	"paketo-buildpacks/yarn-start": packageManagerStart,
+	"paketo-buildpacks/npm-start":  packageManagerStart,
+	"paketo-buildpacks/pnpm-start": packageManagerStart,
+
+	"paketo-buildpacks/nginx": func(app *detectContext) (bool, string) {
+		if app.env["BP_WEB_SERVER"] == "nginx" {
+			return true, "BP_WEB_SERVER is \"nginx\""
+		}
+		return false, "BP_WEB_SERVER is not \"nginx\""
+	},
+
+	"paketo-buildpacks/datadog": func(app *detectContext) (bool, string) {
+		return app.enabled("BP_DATADOG_ENABLED")
+	},
+
+	"paketo-buildpacks/new-relic": func(app *detectContext) (bool, string) {
+		if app.hasBinding("NewRelic") {
+			return true, "found a NewRelic service binding"
+		}
+		return false, "no NewRelic service binding found"
+	},
+
+	"paketo-buildpacks/dynatrace": func(app *detectContext) (bool, string) {
+		if app.hasBinding("Dynatrace") {
+			return true, "found a Dynatrace service binding"
+		}
+		return false, "no Dynatrace service binding found"
+	},
+
+	"paketo-buildpacks/opentelemetry": func(app *detectContext) (bool, string) {
+		if app.hasBinding("otel") {
+			return true, "found an otel service binding"
+		}
+		return app.enabled("BP_OPENTELEMETRY_ENABLED")
+	},
+
+	"paketo-buildpacks/health-checker": func(app *detectContext) (bool, string) {
+		return app.enabled("BP_HEALTH_CHECKER_ENABLED")
+	},
+
+	"paketo-buildpacks/procfile": func(app *detectContext) (bool, string) {
+		if _, err := os.Stat(filepath.Join(app.dir, "Procfile")); err == nil {
+			return true, "found Procfile"
+		}
+		if app.env["BP_PROCFILE_DEFAULT_PROCESS"] != "" {
+			return true, "BP_PROCFILE_DEFAULT_PROCESS is set"
+		}
+		return false, "no Procfile found and BP_PROCFILE_DEFAULT_PROCESS is not set"
+	},
+
+	"paketo-buildpacks/environment-variables": func(app *detectContext) (bool, string) {
+		var names []string
+		for name := range app.env {
+			if strings.HasPrefix(name, "BPE_") {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return false, "no BPE_* variables are set"
+		}
+		sort.Strings(names)
+		return true, fmt.Sprintf("found %s", strings.Join(names, ", "))
+	},
+
+	"paketo-buildpacks/image-labels": func(app *detectContext) (bool, string) {
+		var names []string
+		for name := range app.env {
+			if name == "BP_IMAGE_LABELS" || strings.HasPrefix(name, "BP_OCI_") {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return false, "neither BP_IMAGE_LABELS nor any BP_OCI_* variables are set"
+		}
+		sort.Strings(names)
+		return true, fmt.Sprintf("found %s", strings.Join(names, ", "))
+	},
+}
+
+func packageManagerStart(app *detectContext) (bool, string) {
+	if app.passed["paketo-buildpacks/nextjs"] {
+		return false, "the start command is provided by paketo-buildpacks/nextjs"
+	}
+
+	if app.script("start") {
+		return true, "package.json defines a start script"
+	}
+	return false, "package.json does not define a start script"
+}
+
+type packageJSON struct {
+	Main            string            `json:"main"`
+	PackageManager  string            `json:"packageManager"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// detectContext is what a stub detector can see of the app that is being
+// built.
+type detectContext struct {
+	// dir is the root of the app and projectDir is the directory selected by
+	// BP_NODE_PROJECT_PATH within it.
+	dir        string
+	projectDir string
+
+	env      map[string]string
+	bindings []string
+	pkg      *packageJSON
+
+	// passed records the members of the current order group that have
+	// already passed detection.
+	passed map[string]bool
+}
+
+func newDetectContext(dir string, env map[string]string) (*detectContext, error) {
+	app := &detectContext{
+		dir:        dir,
+		projectDir: filepath.Join(dir, env["BP_NODE_PROJECT_PATH"]),
+		env:        env,
+	}
+
+	content, err := os.ReadFile(filepath.Join(app.projectDir, "package.json"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if err == nil {
+		app.pkg = &packageJSON{}
+		if err := json.Unmarshal(content, app.pkg); err != nil {
+			return nil, fmt.Errorf("failed to parse package.json: %w", err)
+		}
+	}
+
+	if root, ok := env["SERVICE_BINDING_ROOT"]; ok {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service bindings: %w", err)
+		}
+
+		for _, entry := range entries {
+			kind, err := os.ReadFile(filepath.Join(root, entry.Name(), "type"))
+			if err != nil {
+				continue
+			}
+			app.bindings = append(app.bindings, strings.TrimSpace(string(kind)))
+		}
+	}
+
+	return app, nil
+}
+
+func (app *detectContext) enabled(name string) (bool, string) {
+	if app.env[name] == "true" {
+		return true, fmt.Sprintf("%s is \"true\"", name)
+	}
+	return false, fmt.Sprintf("%s is not \"true\"", name)
+}
+
+func (app *detectContext) hasBinding(kind string) bool {
+	return slices.ContainsFunc(app.bindings, func(binding string) bool {
+		return strings.EqualFold(binding, kind)
+	})
+}
+
+func (app *detectContext) script(name string) bool {
+	if app.pkg == nil {
+		return false
+	}
+	_, ok := app.pkg.Scripts[name]
+	return ok
+}
+
+func (app *detectContext) dependsOn(name string) bool {
+	if app.pkg == nil {
+		return false
+	}
+	return app.pkg.Dependencies[name] != "" || app.pkg.DevDependencies[name] != ""
+}
+
+// anyFile reports whether any of the named files exists in the project
+// directory.
+func (app *detectContext) anyFile(names ...string) (bool, string) {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(app.projectDir, name)); err == nil {
+			return true, fmt.Sprintf("found %s", name)
+		}
+	}
+	return false, fmt.Sprintf("no %s file found", strings.Join(names, " or "))
+}
+
+// lockfile reports whether the named lockfile exists in the project
+// directory or, for workspace packages selected by BP_NODE_PROJECT_PATH, at
+// the root of the app.
+func (app *detectContext) lockfile(name string) (bool, string) {
+	if ok, reason := app.anyFile(name); ok {
+		return true, reason
+	}
+
+	if app.projectDir != filepath.Clean(app.dir) {
+		if _, err := os.Stat(filepath.Join(app.dir, name)); err == nil {
+			return true, fmt.Sprintf("found %s at the root of the workspace", name)
+		}
+	}
+	return false, fmt.Sprintf("no %s file found", name)
+}
+
+// launchpoint reports whether the project directory contains a file that
+// node-start would launch.
+func (app *detectContext) launchpoint() (bool, string) {
+	if launchpoint := app.env["BP_LAUNCHPOINT"]; launchpoint != "" {
+		if _, err := os.Stat(filepath.Join(app.projectDir, launchpoint)); err == nil {
+			return true, fmt.Sprintf("found %s from BP_LAUNCHPOINT", launchpoint)
+		}
+		return false, fmt.Sprintf("BP_LAUNCHPOINT %s does not exist", launchpoint)
+	}
+
+	return app.anyFile("server.js", "app.js", "main.js", "index.js")
+}
+
+// nativeAddons reports whether the app or its vendored node_modules contain
+// native addons that node-gyp needs to rebuild.
+func (app *detectContext) nativeAddons() (bool, string) {
+	var found string
+	err := filepath.WalkDir(app.projectDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.Name() == "binding.gyp" || filepath.Ext(entry.Name()) == ".node" {
+			found, err = filepath.Rel(app.projectDir, path)
+			if err != nil {
+				return err
+			}
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Sprintf("failed to search for native addons: %s", err)
+	}
+
+	if found == "" {
+		return false, "no binding.gyp or .node files found"
+	}
+	return true, fmt.Sprintf("found %s", found)
+}
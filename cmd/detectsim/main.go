@@ -0,0 +1,105 @@
+// Command detectsim predicts which order group of the composite buildpack
+// would be selected for an app, without running a build. It runs stub detect
+// logic for every component buildpack in buildpack.toml against the app
+// directory and the BP_* environment variables.
+//
+// Usage:
+//
+//	go run ./cmd/detectsim [--buildpack-toml buildpack.toml] [--env KEY=VALUE]... <app-dir>
+//
+// BP_*, BPE_* and SERVICE_BINDING_ROOT variables are also taken from the
+// environment of the command; --env takes precedence over them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Environ(), os.Stdout, os.Stderr))
+}
+
+// envFlag collects repeated --env KEY=VALUE flags.
+type envFlag map[string]string
+
+func (e envFlag) String() string {
+	return fmt.Sprint(map[string]string(e))
+}
+
+func (e envFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	e[key] = val
+
+	return nil
+}
+
+func run(args, environ []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("detectsim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	env := envFlag{}
+	for _, variable := range environ {
+		key, val, _ := strings.Cut(variable, "=")
+		if strings.HasPrefix(key, "BP_") || strings.HasPrefix(key, "BPE_") || key == "SERVICE_BINDING_ROOT" {
+			env[key] = val
+		}
+	}
+
+	buildpackPath := flags.String("buildpack-toml", "buildpack.toml", "path to the buildpack.toml of the composite buildpack")
+	flags.Var(env, "env", "build environment variable as KEY=VALUE (may be repeated)")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "expected exactly one app directory")
+		return 2
+	}
+
+	simulation, err := Simulate(*buildpackPath, flags.Arg(0), env)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for _, group := range simulation.Groups {
+		if group.Selected {
+			continue
+		}
+
+		failed := group.Results[len(group.Results)-1]
+		fmt.Fprintf(stdout, "order group %d: skipped because %s failed: %s\n", group.Index, failed.ID, failed.Reason)
+	}
+
+	group, ok := simulation.Selected()
+	if !ok {
+		fmt.Fprintln(stdout, "no order group would be selected")
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "order group %d: selected\n", group.Index)
+	for _, result := range group.Results {
+		kind := "required"
+		if result.Optional {
+			kind = "optional"
+		}
+
+		status := "pass"
+		if !result.Pass {
+			status = "skip"
+		}
+
+		fmt.Fprintf(stdout, "  %s %-48s %s  %s\n", status, result.ID+"@"+result.Version, kind, result.Reason)
+	}
+
+	return 0
+}
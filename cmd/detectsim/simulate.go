@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// Result is the outcome of the stub detection of a single member of an order
+// group.
+type Result struct {
+	ID       string
+	Version  string
+	Optional bool
+	Pass     bool
+	Reason   string
+}
+
+// Group is the outcome of the stub detection of an order group. Detection of
+// a group stops at the first non-optional member that fails, just like the
+// lifecycle, so Results can be shorter than the group itself.
+type Group struct {
+	Index    int
+	Selected bool
+	Results  []Result
+}
+
+// Participating returns the ids of the members of the group that passed
+// detection.
+func (g Group) Participating() []string {
+	var ids []string
+	for _, result := range g.Results {
+		if result.Pass {
+			ids = append(ids, result.ID)
+		}
+	}
+
+	return ids
+}
+
+// Simulation lists every order group that was tried, in order. When a group
+// was selected it is the last one in the list.
+type Simulation struct {
+	Groups []Group
+}
+
+// Selected returns the selected order group, if any.
+func (s Simulation) Selected() (Group, bool) {
+	if len(s.Groups) == 0 || !s.Groups[len(s.Groups)-1].Selected {
+		return Group{}, false
+	}
+
+	return s.Groups[len(s.Groups)-1], true
+}
+
+// Simulate runs the stub detect logic of every member of the order groups in
+// the buildpack.toml at buildpackPath against the app in dir and returns the
+// groups that were tried until one was selected.
+func Simulate(buildpackPath, dir string, env map[string]string) (Simulation, error) {
+	buildpack, err := composite.DecodeBuildpack(buildpackPath)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	for i, order := range buildpack.Order {
+		for _, member := range order.Group {
+			if _, ok := detectors[member.ID]; !ok {
+				return Simulation{}, fmt.Errorf("order group %d: no stub detect logic for %s", i+1, member.ID)
+			}
+		}
+	}
+
+	app, err := newDetectContext(dir, env)
+	if err != nil {
+		return Simulation{}, err
+	}
+
+	var simulation Simulation
+	for i, order := range buildpack.Order {
+		group := Group{Index: i + 1, Selected: true}
+		app.passed = map[string]bool{}
+
+		for _, member := range order.Group {
+			pass, reason := detectors[member.ID](app)
+			app.passed[member.ID] = pass
+
+			group.Results = append(group.Results, Result{
+				ID:       member.ID,
+				Version:  member.Version,
+				Optional: member.Optional,
+				Pass:     pass,
+				Reason:   reason,
+			})
+
+			if !pass && !member.Optional {
+				group.Selected = false
+				break
+			}
+		}
+
+		simulation.Groups = append(simulation.Groups, group)
+		if group.Selected {
+			break
+		}
+	}
+
+	return simulation, nil
+}
@@ -0,0 +1,640 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDetectSim(t *testing.T) {
+	suite := spec.New("detectsim", spec.Report(report.Terminal{}))
+	suite("Simulate", testSimulate)
+	suite("Run", testRun)
+	suite.Run(t)
+}
+
+// buildpackNames maps the names that the integration suites look for in the
+// build logs ("Buildpack for <name>") onto buildpack ids.
+var buildpackNames = map[string]string{
+	"Bun":                           "paketo-buildpacks/bun",
+	"Bun Install":                   "paketo-buildpacks/bun-install",
+	"Bun Start":                     "paketo-buildpacks/bun-start",
+	"CA Certificates":               "paketo-buildpacks/ca-certificates",
+	"Chromium":                      "paketo-buildpacks/chromium",
+	"Corepack":                      "paketo-buildpacks/corepack",
+	"CPython":                       "paketo-buildpacks/cpython",
+	"Datadog":                       "paketo-buildpacks/datadog",
+	"Deno":                          "paketo-buildpacks/deno",
+	"Deno Install":                  "paketo-buildpacks/deno-install",
+	"Deno Start":                    "paketo-buildpacks/deno-start",
+	"Dynatrace":                     "paketo-buildpacks/dynatrace",
+	"Environment Variables":         "paketo-buildpacks/environment-variables",
+	"Git CLI":                       "paketo-buildpacks/git-cli",
+	"Health Checker":                "paketo-buildpacks/health-checker",
+	"Image Labels":                  "paketo-buildpacks/image-labels",
+	"New Relic":                     "paketo-buildpacks/new-relic",
+	"Next.js":                       "paketo-buildpacks/nextjs",
+	"Nginx Server":                  "paketo-buildpacks/nginx",
+	"Node Engine":                   "paketo-buildpacks/node-engine",
+	"Node Gyp":                      "paketo-buildpacks/node-gyp",
+	"Node Module Bill of Materials": "paketo-buildpacks/node-module-bom",
+	"Node Run Script":               "paketo-buildpacks/node-run-script",
+	"Node Start":                    "paketo-buildpacks/node-start",
+	"NPM Install":                   "paketo-buildpacks/npm-install",
+	"NPM Start":                     "paketo-buildpacks/npm-start",
+	"OpenTelemetry":                 "paketo-buildpacks/opentelemetry",
+	"Pnpm":                          "paketo-buildpacks/pnpm",
+	"Pnpm Install":                  "paketo-buildpacks/pnpm-install",
+	"Pnpm Start":                    "paketo-buildpacks/pnpm-start",
+	"Procfile":                      "paketo-buildpacks/procfile",
+	"TypeScript":                    "paketo-buildpacks/typescript",
+	"Watchexec":                     "paketo-buildpacks/watchexec",
+	"Yarn":                          "paketo-buildpacks/yarn",
+	"Yarn Install":                  "paketo-buildpacks/yarn-install",
+	"Yarn Start":                    "paketo-buildpacks/yarn-start",
+}
+
+// simulateCase mirrors a build from the integration suites: the app that is
+// built, the build environment and the files the suite adds to the app, along
+// with the order group and the buildpacks the suite expects to see, or not to
+// see, in the logs.
+type simulateCase struct {
+	app      string
+	env      map[string]string
+	files    map[string]string
+	bindings string
+
+	// group lists the required members of the order group that the
+	// buildpacks the suite looks for in the logs belong to.
+	group []string
+
+	participate    []string
+	notParticipate []string
+}
+
+// The required members of each order group of buildpack.toml, which identify
+// the group without depending on its position in the order.
+var (
+	bunGroup       = []string{"paketo-buildpacks/bun", "paketo-buildpacks/bun-install"}
+	denoGroup      = []string{"paketo-buildpacks/deno", "paketo-buildpacks/deno-start"}
+	yarnNginxGroup = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/yarn", "paketo-buildpacks/yarn-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/nginx"}
+	npmNginxGroup  = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install", "paketo-buildpacks/node-run-script", "paketo-buildpacks/nginx"}
+	yarnGroup      = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/yarn", "paketo-buildpacks/yarn-install"}
+	pnpmGroup      = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/pnpm", "paketo-buildpacks/pnpm-install"}
+	npmGroup       = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/npm-install"}
+	nodeStartGroup = []string{"paketo-buildpacks/node-engine", "paketo-buildpacks/node-start"}
+)
+
+// utilityEnv is the build environment of the "optional utility buildpacks"
+// contexts of the integration suites, which also mount the apm_bindings.
+var utilityEnv = map[string]string{
+	"BPE_SOME_VARIABLE":      "some-value",
+	"BP_IMAGE_LABELS":        "some-label=some-value",
+	"BP_LIVE_RELOAD_ENABLED": "true",
+	"BP_DATADOG_ENABLED":     "true",
+}
+
+// healthCheckerEnv is the build environment of the health checker suite.
+var healthCheckerEnv = map[string]string{
+	"BP_HEALTH_CHECKER_ENABLED": "true",
+	"BPE_DEFAULT_THC_PATH":      "/healthz",
+	"BPE_DEFAULT_THC_PORT":      "8080",
+}
+
+var procfile = map[string]string{"Procfile": "procfile: echo Procfile command"}
+
+// The yarn variants of the git_dependency and private_registry apps get their
+// yarn.lock at test time, since it has to point at a local git repository or
+// registry.
+var yarnLockfile = map[string]string{"yarn.lock": "# yarn lockfile v1\n"}
+
+var simulateCases = map[string][]simulateCase{
+	// apm_bindings holds the service bindings of the APM buildpacks rather
+	// than an app, so it is covered by the cases that mount it.
+	"apm_bindings": nil,
+
+	"bun": {
+		{
+			app:            "bun",
+			group:          bunGroup,
+			participate:    []string{"Bun", "Bun Install", "Bun Start"},
+			notParticipate: []string{"Node Engine", "NPM Install", "Yarn Install", "Procfile", "Environment Variables", "Image Labels"},
+		},
+		{
+			app:         "bun",
+			group:       bunGroup,
+			env:         utilityEnv,
+			files:       procfile,
+			participate: []string{"Bun", "Bun Install", "Bun Start", "Procfile", "Environment Variables", "Image Labels", "Watchexec"},
+		},
+	},
+
+	"ca_cert_apps": {
+		{
+			app:         "ca_cert_apps/node_server",
+			group:       nodeStartGroup,
+			bindings:    "ca_cert_apps",
+			participate: []string{"CA Certificates", "Node Engine", "Node Start"},
+		},
+		{
+			app:         "ca_cert_apps/npm_server",
+			group:       npmGroup,
+			bindings:    "ca_cert_apps",
+			participate: []string{"CA Certificates", "Node Engine", "Node Start", "NPM Install", "NPM Start"},
+		},
+		{
+			app:         "ca_cert_apps/yarn_server",
+			group:       yarnGroup,
+			bindings:    "ca_cert_apps",
+			participate: []string{"CA Certificates", "Node Engine", "Yarn", "Node Start", "Yarn Install", "Yarn Start"},
+		},
+	},
+
+	"deno": {
+		{
+			app:         "deno",
+			group:       denoGroup,
+			participate: []string{"Deno", "Deno Install", "Deno Start"},
+		},
+	},
+
+	"deno_no_lockfile": {
+		{
+			app:            "deno_no_lockfile",
+			group:          denoGroup,
+			participate:    []string{"Deno", "Deno Start"},
+			notParticipate: []string{"Deno Install"},
+		},
+	},
+
+	"git_dependency": {
+		{
+			app:         "git_dependency/npm",
+			group:       npmGroup,
+			participate: []string{"Git CLI", "NPM Install"},
+		},
+		{
+			app:         "git_dependency/yarn",
+			group:       yarnGroup,
+			files:       yarnLockfile,
+			participate: []string{"Git CLI", "Yarn Install"},
+		},
+	},
+
+	// testHealthChecker copies health_checker/server.js over each app, which
+	// already contains a server.js, so only the environment changes.
+	"health_checker": {
+		{
+			app:         "no_package_manager",
+			group:       nodeStartGroup,
+			env:         healthCheckerEnv,
+			participate: []string{"Node Engine", "Node Start", "Health Checker"},
+		},
+		{
+			app:         "npm",
+			group:       npmGroup,
+			env:         healthCheckerEnv,
+			participate: []string{"Node Engine", "NPM Install", "NPM Start", "Health Checker"},
+		},
+		{
+			app:         "yarn",
+			group:       yarnGroup,
+			env:         healthCheckerEnv,
+			participate: []string{"Node Engine", "Yarn Install", "Yarn Start", "Health Checker"},
+		},
+	},
+
+	"native_addon": {
+		{
+			app:         "native_addon",
+			group:       nodeStartGroup,
+			participate: []string{"CPython", "Node Engine", "Node Gyp", "Node Start"},
+		},
+	},
+
+	"nextjs": {
+		{
+			app:            "nextjs",
+			group:          npmGroup,
+			participate:    []string{"Node Engine", "NPM Install", "Next.js"},
+			notParticipate: []string{"NPM Start"},
+		},
+		{
+			app:   "nextjs",
+			group: npmGroup,
+			env: map[string]string{
+				"BP_NEXTJS_STANDALONE_ENABLED": "false",
+				"BP_NODE_RUN_SCRIPTS":          "build",
+			},
+			participate: []string{"NPM Start"},
+		},
+	},
+
+	"no_package_manager": {
+		{
+			app:            "no_package_manager",
+			group:          nodeStartGroup,
+			participate:    []string{"Node Engine", "Node Start"},
+			notParticipate: []string{"Procfile", "Environment Variables", "Image Labels"},
+		},
+		{
+			app:         "no_package_manager",
+			group:       nodeStartGroup,
+			env:         utilityEnv,
+			files:       procfile,
+			bindings:    "apm_bindings",
+			participate: []string{"Node Engine", "Node Start", "Procfile", "Environment Variables", "Image Labels", "Datadog", "New Relic", "Dynatrace", "Watchexec"},
+		},
+	},
+
+	"npm": {
+		{
+			app:         "npm",
+			group:       npmGroup,
+			participate: []string{"Node Engine", "NPM Install", "Node Start", "NPM Start", "Node Module Bill of Materials"},
+		},
+		{
+			app:         "npm",
+			group:       npmGroup,
+			env:         withEnv(utilityEnv, "BP_NODE_RUN_SCRIPTS", "some-script"),
+			files:       procfile,
+			bindings:    "apm_bindings",
+			participate: []string{"Watchexec", "Node Engine", "Node Start", "NPM Install", "NPM Start", "Datadog", "New Relic", "Dynatrace", "Node Run Script"},
+		},
+	},
+
+	"npm_no_start_script": {
+		{
+			app:            "npm_no_start_script",
+			group:          npmGroup,
+			participate:    []string{"Node Engine", "NPM Install", "Node Start"},
+			notParticipate: []string{"NPM Start"},
+		},
+	},
+
+	"npm_with_src_dir": {
+		{
+			app:            "npm_with_src_dir",
+			group:          npmGroup,
+			participate:    []string{"Node Engine", "NPM Install", "NPM Start"},
+			notParticipate: []string{"Node Start"},
+		},
+	},
+
+	"npm_workspaces": {
+		{
+			app:         "npm_workspaces",
+			group:       npmGroup,
+			env:         map[string]string{"BP_NODE_PROJECT_PATH": "apps/api"},
+			participate: []string{"Node Engine", "NPM Install", "NPM Start"},
+		},
+		{
+			app:         "npm_workspaces",
+			group:       npmGroup,
+			env:         map[string]string{"BP_NODE_PROJECT_PATH": "apps/web"},
+			participate: []string{"Node Engine", "NPM Install", "NPM Start"},
+		},
+	},
+
+	"opentelemetry": {
+		{
+			app:         "opentelemetry",
+			group:       npmGroup,
+			env:         map[string]string{"BP_OPENTELEMETRY_ENABLED": "true"},
+			participate: []string{"OpenTelemetry"},
+		},
+	},
+
+	"package_manager": {
+		{
+			app:         "package_manager/npm",
+			group:       npmGroup,
+			participate: []string{"Node Engine", "Corepack", "NPM Install", "NPM Start"},
+		},
+		{
+			app:         "package_manager/yarn",
+			group:       yarnGroup,
+			participate: []string{"Node Engine", "Corepack", "Yarn Install", "Yarn Start"},
+		},
+		{
+			app:         "package_manager/pnpm",
+			group:       pnpmGroup,
+			participate: []string{"Node Engine", "Corepack", "Pnpm Install", "Pnpm Start"},
+		},
+		{
+			app:         "package_manager/mismatch",
+			group:       yarnGroup,
+			participate: []string{"Corepack"},
+		},
+	},
+
+	"pnpm": {
+		{
+			app:         "pnpm",
+			group:       pnpmGroup,
+			participate: []string{"Node Engine", "Pnpm", "Pnpm Install", "Node Start", "Pnpm Start"},
+		},
+	},
+
+	"private_registry": {
+		{
+			app:         "private_registry/npm",
+			group:       npmGroup,
+			participate: []string{"NPM Install"},
+		},
+		{
+			app:         "private_registry/yarn",
+			group:       yarnGroup,
+			files:       yarnLockfile,
+			participate: []string{"Yarn Install"},
+		},
+	},
+
+	"puppeteer": {
+		{
+			app:         "puppeteer/npm",
+			group:       npmGroup,
+			participate: []string{"Chromium", "NPM Install"},
+		},
+		{
+			app:         "puppeteer/yarn",
+			group:       yarnGroup,
+			participate: []string{"Chromium", "Yarn Install"},
+		},
+	},
+
+	"static_frontend": {
+		{
+			app:   "static_frontend/npm",
+			group: npmNginxGroup,
+			env: map[string]string{
+				"BP_NODE_RUN_SCRIPTS": "build",
+				"BP_WEB_SERVER":       "nginx",
+				"BP_WEB_SERVER_ROOT":  "build",
+			},
+			participate:    []string{"Node Engine", "NPM Install", "Node Run Script", "Nginx Server"},
+			notParticipate: []string{"Node Start", "NPM Start"},
+		},
+		{
+			app:   "static_frontend/yarn",
+			group: yarnNginxGroup,
+			env: map[string]string{
+				"BP_NODE_RUN_SCRIPTS": "build",
+				"BP_WEB_SERVER":       "nginx",
+				"BP_WEB_SERVER_ROOT":  "build",
+			},
+			participate:    []string{"Node Engine", "Yarn", "Yarn Install", "Node Run Script", "Nginx Server"},
+			notParticipate: []string{"Node Start", "Yarn Start", "NPM Install"},
+		},
+	},
+
+	"typescript": {
+		{
+			app:         "typescript/npm",
+			group:       npmGroup,
+			participate: []string{"TypeScript", "NPM Install"},
+		},
+		{
+			app:         "typescript/yarn",
+			group:       yarnGroup,
+			participate: []string{"TypeScript", "Yarn Install"},
+		},
+		{
+			app:         "typescript/no_package_manager",
+			group:       nodeStartGroup,
+			participate: []string{"TypeScript", "Node Start"},
+		},
+	},
+
+	"vendored": {
+		{
+			app:         "vendored",
+			group:       nodeStartGroup,
+			participate: []string{"Node Engine", "Node Start"},
+		},
+	},
+
+	"yarn": {
+		{
+			app:         "yarn",
+			group:       yarnGroup,
+			participate: []string{"Node Engine", "Yarn", "Yarn Install", "Node Start", "Yarn Start"},
+		},
+		{
+			app:         "yarn",
+			group:       yarnGroup,
+			env:         withEnv(utilityEnv, "BP_NODE_RUN_SCRIPTS", "some-script"),
+			files:       procfile,
+			bindings:    "apm_bindings",
+			participate: []string{"Watchexec", "Node Engine", "Yarn", "Yarn Install", "Node Start", "Yarn Start", "Datadog", "New Relic", "Dynatrace", "Node Run Script"},
+		},
+	},
+
+	"yarn_berry": {
+		{
+			// testYarnBerry generates the lockfile and the PnP loader before
+			// building.
+			app:            "yarn_berry",
+			group:          yarnGroup,
+			files:          map[string]string{"yarn.lock": "", ".pnp.cjs": ""},
+			participate:    []string{"Node Engine", "Yarn", "Yarn Install", "Yarn Start"},
+			notParticipate: []string{"NPM Install"},
+		},
+	},
+
+	"yarn_no_start_script": {
+		{
+			app:            "yarn_no_start_script",
+			group:          yarnGroup,
+			participate:    []string{"Node Engine", "Yarn", "Yarn Install", "Node Start"},
+			notParticipate: []string{"Yarn Start"},
+		},
+	},
+
+	"yarn_with_src_dir": {
+		{
+			app:            "yarn_with_src_dir",
+			group:          yarnGroup,
+			participate:    []string{"Node Engine", "Yarn", "Yarn Install", "Yarn Start"},
+			notParticipate: []string{"Node Start"},
+		},
+	},
+
+	"yarn_workspaces": {
+		{
+			app:         "yarn_workspaces",
+			group:       yarnGroup,
+			env:         map[string]string{"BP_NODE_PROJECT_PATH": "apps/api"},
+			participate: []string{"Node Engine", "Yarn Install", "Yarn Start"},
+		},
+		{
+			app:         "yarn_workspaces",
+			group:       yarnGroup,
+			env:         map[string]string{"BP_NODE_PROJECT_PATH": "apps/web"},
+			participate: []string{"Node Engine", "Yarn Install", "Yarn Start"},
+		},
+	},
+}
+
+func withEnv(env map[string]string, key, value string) map[string]string {
+	merged := map[string]string{key: value}
+	for k, v := range env {
+		merged[k] = v
+	}
+	return merged
+}
+
+func testSimulate(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buildpackPath = filepath.Join("..", "..", "buildpack.toml")
+		testdata      = filepath.Join("..", "..", "integration", "testdata")
+	)
+
+	it("covers every directory in integration/testdata", func() {
+		entries, err := os.ReadDir(testdata)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				Expect(simulateCases).To(HaveKey(entry.Name()))
+			}
+		}
+	})
+
+	it("selects the groups that the integration suites assert from the build logs", func() {
+		for dir, cases := range simulateCases {
+			for _, c := range cases {
+				app := filepath.Join(testdata, c.app)
+				if len(c.files) > 0 {
+					app = t.TempDir()
+					Expect(os.CopyFS(app, os.DirFS(filepath.Join(testdata, c.app)))).To(Succeed())
+
+					for name, content := range c.files {
+						Expect(os.WriteFile(filepath.Join(app, name), []byte(content), 0644)).To(Succeed())
+					}
+				}
+
+				env := map[string]string{}
+				for key, value := range c.env {
+					env[key] = value
+				}
+				if c.bindings != "" {
+					env["SERVICE_BINDING_ROOT"] = filepath.Join(testdata, c.bindings)
+				}
+
+				simulation, err := Simulate(buildpackPath, app, env)
+				Expect(err).NotTo(HaveOccurred())
+
+				group, ok := simulation.Selected()
+				Expect(ok).To(BeTrue(), "%s: no order group selected for %s", dir, c.app)
+
+				var required []string
+				for _, result := range group.Results {
+					if !result.Optional {
+						required = append(required, result.ID)
+					}
+				}
+				Expect(required).To(ConsistOf(c.group), "%s: expected the order group of %v to be selected for %s with %v", dir, c.group, c.app, c.env)
+
+				participating := group.Participating()
+				for _, name := range c.participate {
+					Expect(buildpackNames).To(HaveKey(name))
+					Expect(participating).To(ContainElement(buildpackNames[name]), "%s: expected %s to participate in order group %d for %s with %v", dir, name, group.Index, c.app, c.env)
+				}
+
+				for _, name := range c.notParticipate {
+					Expect(buildpackNames).To(HaveKey(name))
+					Expect(participating).NotTo(ContainElement(buildpackNames[name]), "%s: expected %s not to participate in order group %d for %s with %v", dir, name, group.Index, c.app, c.env)
+				}
+			}
+		}
+	})
+
+	context("when an earlier group fails", func() {
+		it("reports the required member that failed", func() {
+			simulation, err := Simulate(buildpackPath, filepath.Join(testdata, "npm"), nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(simulation.Groups[0].Selected).To(BeFalse())
+
+			results := simulation.Groups[0].Results
+			Expect(results[0].ID).To(Equal("paketo-buildpacks/ca-certificates"))
+			Expect(results[0].Optional).To(BeTrue())
+			Expect(results[0].Pass).To(BeFalse())
+
+			failed := results[len(results)-1]
+			Expect(failed.ID).To(Equal("paketo-buildpacks/bun"))
+			Expect(failed.Optional).To(BeFalse())
+			Expect(failed.Pass).To(BeFalse())
+			Expect(failed.Reason).To(Equal("no bun.lock or bun.lockb file found"))
+		})
+	})
+
+	context("when buildpack.toml contains a buildpack without stub detect logic", func() {
+		it("returns an error", func() {
+			path := filepath.Join(t.TempDir(), "buildpack.toml")
+			Expect(os.WriteFile(path, []byte(`
+[[order]]
+  [[order.group]]
+    id = "some-org/unknown"
+    version = "1.0.0"
+`), 0644)).To(Succeed())
+
+			_, err := Simulate(path, filepath.Join(testdata, "npm"), nil)
+			Expect(err).To(MatchError("order group 1: no stub detect logic for some-org/unknown"))
+		})
+	})
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	it.Before(func() {
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+	})
+
+	it("explains the selected group and every buildpack in it", func() {
+		code := run([]string{
+			"--buildpack-toml", filepath.Join("..", "..", "buildpack.toml"),
+			"--env", "BP_NODE_RUN_SCRIPTS=build",
+			filepath.Join("..", "..", "integration", "testdata", "static_frontend", "npm"),
+		}, []string{"BP_WEB_SERVER=nginx", "HOME=/root"}, stdout, stderr)
+		Expect(code).To(Equal(0), stderr.String())
+
+		Expect(stdout.String()).To(ContainSubstring("order group 1: skipped because paketo-buildpacks/bun failed: no bun.lock or bun.lockb file found"))
+		Expect(stdout.String()).To(ContainSubstring("order group 4: selected"))
+		Expect(stdout.String()).To(MatchRegexp(`pass paketo-buildpacks/node-run-script@\S+\s+required  runs the scripts in BP_NODE_RUN_SCRIPTS \(build\)`))
+		Expect(stdout.String()).To(MatchRegexp(`pass paketo-buildpacks/nginx@\S+\s+required  BP_WEB_SERVER is "nginx"`))
+		Expect(stdout.String()).To(MatchRegexp(`skip paketo-buildpacks/environment-variables@\S+\s+optional  no BPE_\* variables are set`))
+	})
+
+	context("when no app directory is given", func() {
+		it("exits with an error", func() {
+			code := run(nil, nil, stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("expected exactly one app directory"))
+		})
+	})
+
+	context("when an --env flag is malformed", func() {
+		it("exits with an error", func() {
+			code := run([]string{"--env", "BP_WEB_SERVER", "."}, nil, stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring(`expected KEY=VALUE, got "BP_WEB_SERVER"`))
+		})
+	})
+}
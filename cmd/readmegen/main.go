@@ -0,0 +1,61 @@
+// Command readmegen renders the lists of component and utility buildpacks in
+// README.md from the order groups in buildpack.toml.
+//
+// Usage:
+//
+//	go run ./cmd/readmegen [--buildpack-toml buildpack.toml] [--readme README.md] [--check]
+//
+// With --check the README is left untouched and the command exits with a
+// non-zero status if it is out of date.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("readmegen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	buildpackPath := flags.String("buildpack-toml", "buildpack.toml", "path to the buildpack.toml of the composite buildpack")
+	readmePath := flags.String("readme", "README.md", "path to the README to update")
+	check := flags.Bool("check", false, "fail instead of updating the README when it is out of date")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	current, generated, err := Generate(*buildpackPath, *readmePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if current == generated {
+		fmt.Fprintf(stdout, "%s is up to date\n", *readmePath)
+		return 0
+	}
+
+	if *check {
+		fmt.Fprintf(stderr, "%s is out of date with %s, run `go run ./cmd/readmegen` to update it\n", *readmePath, *buildpackPath)
+		return 1
+	}
+
+	err = os.WriteFile(*readmePath, []byte(generated), 0644)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	fmt.Fprintf(stdout, "updated %s\n", *readmePath)
+
+	return 0
+}
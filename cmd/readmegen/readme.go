@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// buildpackInfo describes how a component buildpack is listed in the README.
+type buildpackInfo struct {
+	ID   string
+	Name string
+
+	// Utility buildpacks are listed separately from the buildpacks that
+	// provide the Node.js, Bun and Deno toolchains and start commands.
+	Utility bool
+}
+
+// buildpacks lists every buildpack that may appear in buildpack.toml, in the
+// order they are listed in the README.
+var buildpacks = []buildpackInfo{
+	{ID: "paketo-buildpacks/node-engine", Name: "Node Engine"},
+	{ID: "paketo-buildpacks/yarn", Name: "Yarn"},
+	{ID: "paketo-buildpacks/yarn-install", Name: "Yarn Install"},
+	{ID: "paketo-buildpacks/npm-install", Name: "NPM Install"},
+	{ID: "paketo-buildpacks/pnpm", Name: "Pnpm"},
+	{ID: "paketo-buildpacks/pnpm-install", Name: "Pnpm Install"},
+	{ID: "paketo-buildpacks/yarn-start", Name: "Yarn Start"},
+	{ID: "paketo-buildpacks/npm-start", Name: "NPM Start"},
+	{ID: "paketo-buildpacks/pnpm-start", Name: "Pnpm Start"},
+	{ID: "paketo-buildpacks/node-start", Name: "Node Start"},
+	{ID: "paketo-buildpacks/corepack", Name: "Corepack"},
+	{ID: "paketo-buildpacks/nextjs", Name: "Next.js"},
+	{ID: "paketo-buildpacks/typescript", Name: "TypeScript"},
+	{ID: "paketo-buildpacks/nginx", Name: "Nginx Server"},
+	{ID: "paketo-buildpacks/bun", Name: "Bun"},
+	{ID: "paketo-buildpacks/bun-install", Name: "Bun Install"},
+	{ID: "paketo-buildpacks/bun-start", Name: "Bun Start"},
+	{ID: "paketo-buildpacks/deno", Name: "Deno"},
+	{ID: "paketo-buildpacks/deno-install", Name: "Deno Install"},
+	{ID: "paketo-buildpacks/deno-start", Name: "Deno Start"},
+
+	{ID: "paketo-buildpacks/procfile", Name: "Procfile", Utility: true},
+	{ID: "paketo-buildpacks/environment-variables", Name: "Environment Variables", Utility: true},
+	{ID: "paketo-buildpacks/image-labels", Name: "Image Labels", Utility: true},
+	{ID: "paketo-buildpacks/ca-certificates", Name: "CA Certificates", Utility: true},
+	{ID: "paketo-buildpacks/node-run-script", Name: "Node Run Script", Utility: true},
+	{ID: "paketo-buildpacks/node-module-bom", Name: "Node Module Bill of Materials", Utility: true},
+	{ID: "paketo-buildpacks/watchexec", Name: "Watchexec", Utility: true},
+	{ID: "paketo-buildpacks/tini", Name: "Tini", Utility: true},
+	{ID: "paketo-buildpacks/cpython", Name: "CPython", Utility: true},
+	{ID: "paketo-buildpacks/node-gyp", Name: "Node Gyp", Utility: true},
+	{ID: "paketo-buildpacks/git-cli", Name: "Git CLI", Utility: true},
+	{ID: "paketo-buildpacks/datadog", Name: "Datadog", Utility: true},
+	{ID: "paketo-buildpacks/new-relic", Name: "New Relic", Utility: true},
+	{ID: "paketo-buildpacks/dynatrace", Name: "Dynatrace", Utility: true},
+	{ID: "paketo-buildpacks/opentelemetry", Name: "OpenTelemetry", Utility: true},
+	{ID: "paketo-buildpacks/health-checker", Name: "Health Checker", Utility: true},
+	{ID: "paketo-buildpacks/chromium", Name: "Chromium", Utility: true},
+}
+
+// usage records where a buildpack appears in the order groups.
+type usage struct {
+	versions []string
+	groups   []int
+	optional []int
+}
+
+// Sections renders the generated sections of the README from the
+// buildpack.toml at path, keyed by section name.
+func Sections(path string) (map[string]string, error) {
+	buildpack, err := composite.DecodeBuildpack(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, info := range buildpacks {
+		names[info.ID] = info.Name
+	}
+
+	usages := map[string]*usage{}
+	var legend []string
+	for i, order := range buildpack.Order {
+		var required []string
+		for _, member := range order.Group {
+			name, ok := names[member.ID]
+			if !ok {
+				return nil, fmt.Errorf("order group %d: %s is not listed in cmd/readmegen, add its name to the buildpacks list", i+1, member.ID)
+			}
+
+			u, ok := usages[member.ID]
+			if !ok {
+				u = &usage{}
+				usages[member.ID] = u
+			}
+
+			if !slices.Contains(u.versions, member.Version) {
+				u.versions = append(u.versions, member.Version)
+			}
+			u.groups = append(u.groups, i+1)
+
+			if member.Optional {
+				u.optional = append(u.optional, i+1)
+			} else {
+				required = append(required, name)
+			}
+		}
+		legend = append(legend, fmt.Sprintf("%d. %s", i+1, strings.Join(required, ", ")))
+	}
+
+	return map[string]string{
+		"components": table(usages, false) + "\n" +
+			"The order groups are tried in this order, and the first group whose\n" +
+			"required buildpacks all pass detection is used:\n\n" +
+			strings.Join(legend, "\n") + "\n",
+		"utilities": table(usages, true),
+	}, nil
+}
+
+func table(usages map[string]*usage, utility bool) string {
+	var b strings.Builder
+	b.WriteString("| Buildpack | ID | Version | Optional | Order groups |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, info := range buildpacks {
+		u, ok := usages[info.ID]
+		if !ok || info.Utility != utility {
+			continue
+		}
+
+		optional := "no"
+		switch {
+		case len(u.optional) == len(u.groups):
+			optional = "yes"
+		case len(u.optional) > 0:
+			optional = "in " + joinInts(u.optional)
+		}
+
+		fmt.Fprintf(&b, "| [%s CNB](https://github.com/%s) | `%s` | %s | %s | %s |\n",
+			info.Name, info.ID, info.ID, strings.Join(u.versions, ", "), optional, joinInts(u.groups))
+	}
+
+	return b.String()
+}
+
+func joinInts(values []int) string {
+	var s []string
+	for _, v := range values {
+		s = append(s, fmt.Sprint(v))
+	}
+
+	return strings.Join(s, ", ")
+}
+
+// Render replaces the content between the markers of every generated section
+// in readme with the section rendered from buildpack.toml. The markers look
+// like:
+//
+//	<!-- BEGIN readmegen: components -->
+//	<!-- END readmegen: components -->
+func Render(readme string, sections map[string]string) (string, error) {
+	for _, name := range []string{"components", "utilities"} {
+		begin := fmt.Sprintf("<!-- BEGIN readmegen: %s -->\n", name)
+		end := fmt.Sprintf("<!-- END readmegen: %s -->", name)
+
+		start := strings.Index(readme, begin)
+		if start == -1 {
+			return "", fmt.Errorf("README is missing the %q marker", strings.TrimSpace(begin))
+		}
+		start += len(begin)
+
+		stop := strings.Index(readme[start:], end)
+		if stop == -1 {
+			return "", fmt.Errorf("README is missing the %q marker", end)
+		}
+
+		readme = readme[:start] + sections[name] + readme[start+stop:]
+	}
+
+	return readme, nil
+}
+
+// Generate renders the generated sections of the README at readmePath from
+// the buildpack.toml at buildpackPath and returns the current and the
+// generated content of the README.
+func Generate(buildpackPath, readmePath string) (string, string, error) {
+	sections, err := Sections(buildpackPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	content, err := os.ReadFile(readmePath)
+	if err != nil {
+		return "", "", err
+	}
+
+	generated, err := Render(string(content), sections)
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", readmePath, err)
+	}
+
+	return string(content), generated, nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReadmeGen(t *testing.T) {
+	suite := spec.New("readmegen", spec.Report(report.Terminal{}))
+	suite("Sections", testSections)
+	suite("Run", testRun)
+	suite.Run(t)
+}
+
+const readmeTemplate = `# Some Buildpack
+
+These buildpacks include:
+
+<!-- BEGIN readmegen: components -->
+- [Outdated CNB](https://github.com/paketo-buildpacks/outdated)
+<!-- END readmegen: components -->
+
+Utility buildpacks:
+
+<!-- BEGIN readmegen: utilities -->
+<!-- END readmegen: utilities -->
+
+More text.
+`
+
+const fixtureBuildpackTOML = `
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/ca-certificates"
+    optional = true
+    version = "3.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/npm-install"
+    version = "2.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-start"
+    optional = true
+    version = "3.0.0"
+
+[[order]]
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/node-start"
+    version = "3.0.0"
+`
+
+func testSections(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("for the buildpack.toml of the repository", func() {
+		var (
+			buildpack composite.Buildpack
+			sections  map[string]string
+		)
+
+		it.Before(func() {
+			path := filepath.Join("..", "..", "buildpack.toml")
+
+			var err error
+			buildpack, err = composite.DecodeBuildpack(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			sections, err = Sections(path)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it("lists every buildpack of the order groups in the groups it belongs to", func() {
+			groups := map[string][]string{}
+			for i, order := range buildpack.Order {
+				for _, member := range order.Group {
+					groups[member.ID] = append(groups[member.ID], strconv.Itoa(i+1))
+				}
+			}
+
+			rows := sections["components"] + sections["utilities"]
+			for id, ids := range groups {
+				Expect(rows).To(MatchRegexp("(?m)^\\| \\[[^]]+ CNB\\]\\(https://github.com/%s\\) \\| `%s` \\| [^|]+ \\| [^|]+ \\| %s \\|$",
+					regexp.QuoteMeta(id), regexp.QuoteMeta(id), strings.Join(ids, ", ")), id)
+			}
+
+			Expect(sections["utilities"]).To(ContainSubstring("`paketo-buildpacks/cpython`"))
+			Expect(sections["utilities"]).To(ContainSubstring("`paketo-buildpacks/node-module-bom`"))
+			Expect(sections["components"]).NotTo(ContainSubstring("`paketo-buildpacks/cpython`"))
+		})
+
+		it("lists the required buildpacks of every order group", func() {
+			legend := regexp.MustCompile(`(?m)^(\d+)\. `).FindAllStringSubmatch(sections["components"], -1)
+			Expect(legend).To(HaveLen(len(buildpack.Order)))
+			for i, match := range legend {
+				Expect(match[1]).To(Equal(strconv.Itoa(i + 1)))
+			}
+		})
+	})
+
+	context("for a small buildpack.toml", func() {
+		it("renders the sections", func() {
+			path := filepath.Join(t.TempDir(), "buildpack.toml")
+			Expect(os.WriteFile(path, []byte(fixtureBuildpackTOML), 0644)).To(Succeed())
+
+			sections, err := Sections(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			readme, err := Render(readmeTemplate, sections)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(readme).To(Equal(`# Some Buildpack
+
+These buildpacks include:
+
+<!-- BEGIN readmegen: components -->
+| Buildpack | ID | Version | Optional | Order groups |
+| --- | --- | --- | --- | --- |
+| [Node Engine CNB](https://github.com/paketo-buildpacks/node-engine) | ` + "`paketo-buildpacks/node-engine`" + ` | 1.0.0 | no | 1, 2 |
+| [NPM Install CNB](https://github.com/paketo-buildpacks/npm-install) | ` + "`paketo-buildpacks/npm-install`" + ` | 2.0.0 | no | 1 |
+| [Node Start CNB](https://github.com/paketo-buildpacks/node-start) | ` + "`paketo-buildpacks/node-start`" + ` | 3.0.0 | in 1 | 1, 2 |
+
+The order groups are tried in this order, and the first group whose
+required buildpacks all pass detection is used:
+
+1. Node Engine, NPM Install
+2. Node Engine, Node Start
+<!-- END readmegen: components -->
+
+Utility buildpacks:
+
+<!-- BEGIN readmegen: utilities -->
+| Buildpack | ID | Version | Optional | Order groups |
+| --- | --- | --- | --- | --- |
+| [CA Certificates CNB](https://github.com/paketo-buildpacks/ca-certificates) | ` + "`paketo-buildpacks/ca-certificates`" + ` | 3.0.0 | yes | 1 |
+<!-- END readmegen: utilities -->
+
+More text.
+`))
+		})
+	})
+
+	context("when a buildpack is used with different versions", func() {
+		it("lists every version in the order of the groups", func() {
+			path := filepath.Join(t.TempDir(), "buildpack.toml")
+			Expect(os.WriteFile(path, []byte(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.1.0"
+
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`), 0644)).To(Succeed())
+
+			sections, err := Sections(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sections["components"]).To(ContainSubstring("| `paketo-buildpacks/node-engine` | 1.0.0, 1.1.0 | no | 1, 2, 3 |\n"))
+		})
+	})
+
+	context("when buildpack.toml contains a buildpack without a name", func() {
+		it("returns an error", func() {
+			path := filepath.Join(t.TempDir(), "buildpack.toml")
+			Expect(os.WriteFile(path, []byte(`
+[[order]]
+  [[order.group]]
+    id = "some-org/unknown"
+    version = "1.0.0"
+`), 0644)).To(Succeed())
+
+			_, err := Sections(path)
+			Expect(err).To(MatchError(ContainSubstring("order group 1: some-org/unknown is not listed in cmd/readmegen")))
+		})
+	})
+
+	context("when the README is missing a marker", func() {
+		it("returns an error", func() {
+			_, err := Render("# Some Buildpack\n<!-- BEGIN readmegen: components -->\n", map[string]string{})
+			Expect(err).To(MatchError(`README is missing the "<!-- END readmegen: components -->" marker`))
+		})
+	})
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buildpackPath string
+		readmePath    string
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	it.Before(func() {
+		dir := t.TempDir()
+		buildpackPath = filepath.Join(dir, "buildpack.toml")
+		readmePath = filepath.Join(dir, "README.md")
+
+		Expect(os.WriteFile(buildpackPath, []byte(fixtureBuildpackTOML), 0644)).To(Succeed())
+		Expect(os.WriteFile(readmePath, []byte(readmeTemplate), 0644)).To(Succeed())
+
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+	})
+
+	it("updates the README", func() {
+		code := run([]string{"--buildpack-toml", buildpackPath, "--readme", readmePath}, stdout, stderr)
+		Expect(code).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal("updated " + readmePath + "\n"))
+
+		content, err := os.ReadFile(readmePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("| [Node Engine CNB]"))
+		Expect(string(content)).NotTo(ContainSubstring("Outdated CNB"))
+
+		stdout.Reset()
+		code = run([]string{"--buildpack-toml", buildpackPath, "--readme", readmePath, "--check"}, stdout, stderr)
+		Expect(code).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal(readmePath + " is up to date\n"))
+	})
+
+	context("when checking a README that is out of date", func() {
+		it("fails and leaves the README untouched", func() {
+			code := run([]string{"--buildpack-toml", buildpackPath, "--readme", readmePath, "--check"}, stdout, stderr)
+			Expect(code).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("is out of date with " + buildpackPath))
+
+			content, err := os.ReadFile(readmePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(readmeTemplate))
+		})
+	})
+}
@@ -0,0 +1,65 @@
+// Command releasenotes compares two revisions of buildpack.toml and
+// package.toml and prints markdown release notes for the composite buildpack:
+// the components that were added, removed or bumped, the order groups that
+// were added, removed or moved, the members that became optional or required,
+// and the semver bump that the changes imply.
+//
+// Usage:
+//
+//	go run ./cmd/releasenotes <from> [<to>]
+//
+// Each revision is either a directory containing buildpack.toml and
+// package.toml or a git revision of the repository in the working directory,
+// such as a release tag. <to> defaults to the working directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("releasenotes", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		fmt.Fprintln(stderr, "usage: releasenotes <from> [<to>]")
+		return 2
+	}
+
+	from, to := flags.Arg(0), "."
+	if flags.NArg() == 2 {
+		to = flags.Arg(1)
+	}
+
+	old, err := LoadRevision(from)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	new, err := LoadRevision(to)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if to == "." {
+		to = "the working tree"
+	}
+
+	fmt.Fprint(stdout, Diff(old, new).Markdown(from, to))
+
+	return 0
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// Change is a single line of the release notes.
+type Change struct {
+	Description string
+	Bump        composite.Bump
+}
+
+// GroupChange lists the changes to an order group. Index is the position of
+// the group in the new revision, or in the old one for a removed group.
+type GroupChange struct {
+	Index   int
+	Label   string
+	Added   bool
+	Removed bool
+	Changes []Change
+}
+
+// Notes are the differences between two revisions of the composite
+// buildpack.
+type Notes struct {
+	// Components lists the changes to the dependencies in package.toml.
+	Components []Change
+	Groups     []GroupChange
+}
+
+// Bump returns the largest bump implied by any of the changes.
+func (n Notes) Bump() composite.Bump {
+	bump := composite.BumpNone
+	for _, change := range n.Components {
+		bump = max(bump, change.Bump)
+	}
+	for _, group := range n.Groups {
+		for _, change := range group.Changes {
+			bump = max(bump, change.Bump)
+		}
+	}
+
+	return bump
+}
+
+// Diff compares two revisions of the composite buildpack.
+//
+// Removing a component or making it required can break apps that built with
+// the old revision, so those changes imply a major bump. So does moving an
+// order group, since an app that passes detection for more than one group can
+// end up with a different one. Adding optional components or order groups
+// implies a minor bump, and component version bumps imply the same bump as the
+// component itself.
+//
+// The version of a member is only listed under its order group when it
+// changed differently from the dependency in package.toml, which already
+// lists every component bump.
+func Diff(old, new Revision) Notes {
+	var notes Notes
+
+	for _, id := range composite.SortedKeys(old.Dependencies) {
+		if _, ok := new.Dependencies[id]; !ok {
+			notes.Components = append(notes.Components, Change{fmt.Sprintf("Removed `%s` (was %s)", id, old.Dependencies[id]), composite.BumpMajor})
+		}
+	}
+
+	for _, id := range composite.SortedKeys(new.Dependencies) {
+		previous, ok := old.Dependencies[id]
+		if !ok {
+			notes.Components = append(notes.Components, Change{fmt.Sprintf("Added `%s` %s", id, new.Dependencies[id]), composite.BumpMinor})
+			continue
+		}
+
+		if change, ok := versionChange(id, previous, new.Dependencies[id]); ok {
+			notes.Components = append(notes.Components, change)
+		}
+	}
+
+	matches := matchGroups(old.Groups, new.Groups)
+	moved := movedGroups(matches)
+
+	matched := map[int]bool{}
+	for newIndex, group := range new.Groups {
+		oldIndex, ok := matches[newIndex]
+		if !ok {
+			change := GroupChange{Index: newIndex + 1, Label: groupLabel(group), Added: true}
+			for _, member := range group {
+				change.Changes = append(change.Changes, Change{memberDescription(member), composite.BumpMinor})
+			}
+			notes.Groups = append(notes.Groups, change)
+			continue
+		}
+		matched[oldIndex] = true
+
+		change := GroupChange{Index: newIndex + 1, Label: groupLabel(group)}
+		if moved[newIndex] {
+			change.Changes = append(change.Changes, Change{fmt.Sprintf("Moved from position %d to %d", oldIndex+1, newIndex+1), composite.BumpMajor})
+		}
+		change.Changes = append(change.Changes, diffGroup(old, new, old.Groups[oldIndex], group)...)
+		if len(change.Changes) > 0 {
+			notes.Groups = append(notes.Groups, change)
+		}
+	}
+
+	for oldIndex, group := range old.Groups {
+		if matched[oldIndex] {
+			continue
+		}
+
+		change := GroupChange{Index: oldIndex + 1, Label: groupLabel(group), Removed: true}
+		for _, member := range group {
+			change.Changes = append(change.Changes, Change{memberDescription(member), composite.BumpMajor})
+		}
+		notes.Groups = append(notes.Groups, change)
+	}
+
+	return notes
+}
+
+// diffGroup compares two revisions of the same order group of the old and
+// new revision of the composite buildpack.
+func diffGroup(oldRevision, newRevision Revision, old, new []Member) []Change {
+	previous := map[string]Member{}
+	for _, member := range old {
+		previous[member.ID] = member
+	}
+
+	current := map[string]bool{}
+
+	var changes []Change
+	for _, member := range new {
+		current[member.ID] = true
+
+		before, ok := previous[member.ID]
+		if !ok {
+			bump := composite.BumpMinor
+			if !member.Optional {
+				bump = composite.BumpMajor
+			}
+			changes = append(changes, Change{"Added " + memberDescription(member), bump})
+			continue
+		}
+
+		followsPackage := before.Version == oldRevision.Dependencies[member.ID] && member.Version == newRevision.Dependencies[member.ID]
+		if change, ok := versionChange(member.ID, before.Version, member.Version); ok && !followsPackage {
+			changes = append(changes, change)
+		}
+
+		switch {
+		case before.Optional && !member.Optional:
+			changes = append(changes, Change{fmt.Sprintf("`%s` is now required", member.ID), composite.BumpMajor})
+		case !before.Optional && member.Optional:
+			changes = append(changes, Change{fmt.Sprintf("`%s` is now optional", member.ID), composite.BumpMinor})
+		}
+	}
+
+	for _, member := range old {
+		if !current[member.ID] {
+			changes = append(changes, Change{"Removed " + memberDescription(member), composite.BumpMajor})
+		}
+	}
+
+	return changes
+}
+
+// matchGroups pairs every order group of the new revision with the group of
+// the old revision it most likely evolved from, and returns the index of the
+// old group keyed by the index of the new one. Groups are first paired when
+// their required members are identical, then when they share at least half
+// of their required members. Groups left unpaired were added or removed.
+func matchGroups(old, new [][]Member) map[int]int {
+	matches := map[int]int{}
+	taken := map[int]bool{}
+
+	for newIndex, group := range new {
+		for oldIndex, candidate := range old {
+			if !taken[oldIndex] && similarity(required(candidate), required(group)) == 1 {
+				matches[newIndex] = oldIndex
+				taken[oldIndex] = true
+				break
+			}
+		}
+	}
+
+	for newIndex, group := range new {
+		if _, ok := matches[newIndex]; ok {
+			continue
+		}
+
+		best, bestScore := -1, 0.5
+		for oldIndex, candidate := range old {
+			if taken[oldIndex] {
+				continue
+			}
+
+			if score := similarity(required(candidate), required(group)); score >= bestScore && (best == -1 || score > bestScore) {
+				best, bestScore = oldIndex, score
+			}
+		}
+
+		if best != -1 {
+			matches[newIndex] = best
+			taken[best] = true
+		}
+	}
+
+	return matches
+}
+
+// movedGroups returns the new indexes of the matched order groups whose
+// position relative to the other matched groups changed. Adding or removing a
+// group shifts the groups after it without changing the order in which they
+// are tried, so the groups that kept their relative order are the longest run
+// of matches whose old indexes increase along with the new ones, and every
+// other matched group was moved.
+func movedGroups(matches map[int]int) map[int]bool {
+	var newIndexes []int
+	for newIndex := range matches {
+		newIndexes = append(newIndexes, newIndex)
+	}
+	slices.Sort(newIndexes)
+
+	// length[i] is the length of the longest run that ends with newIndexes[i],
+	// and previous[i] the position of the match before it in that run.
+	length := make([]int, len(newIndexes))
+	previous := make([]int, len(newIndexes))
+	last := -1
+	for i, newIndex := range newIndexes {
+		length[i], previous[i] = 1, -1
+		for j := range i {
+			if matches[newIndexes[j]] < matches[newIndex] && length[j]+1 > length[i] {
+				length[i], previous[i] = length[j]+1, j
+			}
+		}
+
+		if last == -1 || length[i] > length[last] {
+			last = i
+		}
+	}
+
+	kept := map[int]bool{}
+	for i := last; i != -1; i = previous[i] {
+		kept[newIndexes[i]] = true
+	}
+
+	moved := map[int]bool{}
+	for _, newIndex := range newIndexes {
+		if !kept[newIndex] {
+			moved[newIndex] = true
+		}
+	}
+
+	return moved
+}
+
+// similarity returns the Jaccard index of two sets of ids.
+func similarity(a, b []string) float64 {
+	union := map[string]bool{}
+	for _, id := range a {
+		union[id] = true
+	}
+
+	intersection := 0
+	for _, id := range b {
+		if union[id] {
+			intersection++
+		}
+		union[id] = true
+	}
+
+	if len(union) == 0 {
+		return 1
+	}
+
+	return float64(intersection) / float64(len(union))
+}
+
+func required(group []Member) []string {
+	var ids []string
+	for _, member := range group {
+		if !member.Optional {
+			ids = append(ids, member.ID)
+		}
+	}
+
+	return ids
+}
+
+// groupLabel names an order group after its required members, which is what
+// sets it apart from the other groups.
+func groupLabel(group []Member) string {
+	var names []string
+	for _, id := range required(group) {
+		names = append(names, id[strings.LastIndex(id, "/")+1:])
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func memberDescription(member Member) string {
+	description := fmt.Sprintf("`%s` %s", member.ID, member.Version)
+	if member.Optional {
+		description += " (optional)"
+	}
+
+	return description
+}
+
+func versionChange(id, old, new string) (Change, bool) {
+	if old == new {
+		return Change{}, false
+	}
+
+	bump, downgrade := compareVersions(old, new)
+
+	verb := "Bumped"
+	if downgrade {
+		verb = "Downgraded"
+	}
+
+	return Change{fmt.Sprintf("%s `%s` from %s to %s (%s)", verb, id, old, new, bump), bump}, true
+}
+
+// compareVersions returns the semver component that differs between two
+// versions and whether the new version is lower. Versions that are not
+// semver are treated as a major change.
+func compareVersions(old, new string) (composite.Bump, bool) {
+	a, okA := composite.ParseVersion(old)
+	b, okB := composite.ParseVersion(new)
+	if !okA || !okB {
+		return composite.BumpMajor, false
+	}
+
+	return composite.BumpBetween(a, b), composite.CompareVersions(a, b) > 0
+}
+
+// Markdown renders the release notes for the changes from one revision to
+// another.
+func (n Notes) Markdown(from, to string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Changes from %s to %s\n\n", from, to)
+	fmt.Fprintf(&b, "Implied version bump: **%s**\n", n.Bump())
+
+	if len(n.Components) == 0 && len(n.Groups) == 0 {
+		b.WriteString("\nNo component buildpacks changed.\n")
+		return b.String()
+	}
+
+	if len(n.Components) > 0 {
+		b.WriteString("\n### Components\n\n")
+		for _, change := range n.Components {
+			fmt.Fprintf(&b, "- %s\n", change.Description)
+		}
+	}
+
+	if len(n.Groups) > 0 {
+		b.WriteString("\n### Order groups\n")
+		for _, group := range n.Groups {
+			heading := "Order group"
+			switch {
+			case group.Added:
+				heading = "Added order group"
+			case group.Removed:
+				heading = "Removed order group"
+			}
+
+			fmt.Fprintf(&b, "\n#### %s %d (%s)\n\n", heading, group.Index, group.Label)
+			for _, change := range group.Changes {
+				fmt.Fprintf(&b, "- %s\n", change.Description)
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	suite := spec.New("releasenotes", spec.Report(report.Terminal{}))
+	suite("Diff", testDiff)
+	suite("Run", testRun)
+	suite.Run(t)
+}
+
+func testDiff(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	// Each directory in testdata holds an old and a new revision of
+	// buildpack.toml and package.toml, and the release notes expected for
+	// the changes between them in expected.md.
+	for _, c := range []struct {
+		name string
+		bump composite.Bump
+	}{
+		{"no_changes", composite.BumpNone},
+		{"patch_bumps", composite.BumpPatch},
+		{"optional_component_added", composite.BumpMinor},
+		{"group_added", composite.BumpMinor},
+		{"breaking_changes", composite.BumpMajor},
+		{"group_moved", composite.BumpMajor},
+	} {
+		context(c.name, func() {
+			it("renders the expected release notes", func() {
+				dir := filepath.Join("testdata", c.name)
+
+				old, err := LoadRevision(filepath.Join(dir, "old"))
+				Expect(err).NotTo(HaveOccurred())
+
+				new, err := LoadRevision(filepath.Join(dir, "new"))
+				Expect(err).NotTo(HaveOccurred())
+
+				expected, err := os.ReadFile(filepath.Join(dir, "expected.md"))
+				Expect(err).NotTo(HaveOccurred())
+
+				notes := Diff(old, new)
+				Expect(notes.Bump()).To(Equal(c.bump))
+				Expect(notes.Markdown("old", "new")).To(Equal(string(expected)))
+			})
+		})
+	}
+
+	context("when a component version is not semver", func() {
+		it("implies a major bump", func() {
+			notes := Diff(
+				Revision{Dependencies: map[string]string{"some-org/some-buildpack": "1.0.0"}},
+				Revision{Dependencies: map[string]string{"some-org/some-buildpack": "latest"}},
+			)
+
+			Expect(notes.Components).To(Equal([]Change{
+				{"Bumped `some-org/some-buildpack` from 1.0.0 to latest (major)", composite.BumpMajor},
+			}))
+		})
+	})
+
+	context("when a member version differs from package.toml", func() {
+		it("lists the version under that order group only", func() {
+			notes := Diff(
+				Revision{
+					Groups:       [][]Member{{{ID: "some-org/engine", Version: "1.0.0"}}, {{ID: "some-org/engine", Version: "1.0.0"}, {ID: "some-org/start", Version: "1.0.0"}}},
+					Dependencies: map[string]string{"some-org/engine": "1.0.0"},
+				},
+				Revision{
+					Groups:       [][]Member{{{ID: "some-org/engine", Version: "1.1.0"}}, {{ID: "some-org/engine", Version: "1.0.1"}, {ID: "some-org/start", Version: "1.0.0"}}},
+					Dependencies: map[string]string{"some-org/engine": "1.1.0"},
+				},
+			)
+
+			Expect(notes.Components).To(Equal([]Change{
+				{"Bumped `some-org/engine` from 1.0.0 to 1.1.0 (minor)", composite.BumpMinor},
+			}))
+			Expect(notes.Groups).To(Equal([]GroupChange{
+				{
+					Index:   2,
+					Label:   "engine, start",
+					Changes: []Change{{"Bumped `some-org/engine` from 1.0.0 to 1.0.1 (patch)", composite.BumpPatch}},
+				},
+			}))
+		})
+	})
+
+	context("when a member becomes optional", func() {
+		it("implies a minor bump", func() {
+			notes := Diff(
+				Revision{Groups: [][]Member{{{ID: "some-org/engine", Version: "1.0.0"}, {ID: "some-org/start", Version: "1.0.0"}}}},
+				Revision{Groups: [][]Member{{{ID: "some-org/engine", Version: "1.0.0"}, {ID: "some-org/start", Version: "1.0.0", Optional: true}}}},
+			)
+
+			Expect(notes.Groups).To(Equal([]GroupChange{
+				{
+					Index:   1,
+					Label:   "engine",
+					Changes: []Change{{"`some-org/start` is now optional", composite.BumpMinor}},
+				},
+			}))
+			Expect(notes.Bump()).To(Equal(composite.BumpMinor))
+		})
+	})
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	it.Before(func() {
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+	})
+
+	context("when given two directories", func() {
+		it("prints the release notes", func() {
+			dir := filepath.Join("testdata", "patch_bumps")
+
+			code := run([]string{filepath.Join(dir, "old"), filepath.Join(dir, "new")}, stdout, stderr)
+			Expect(code).To(Equal(0), stderr.String())
+
+			Expect(stdout.String()).To(ContainSubstring("Implied version bump: **patch**"))
+			Expect(stdout.String()).To(ContainSubstring("- Bumped `paketo-buildpacks/node-engine` from 1.0.0 to 1.0.1 (patch)"))
+		})
+	})
+
+	context("when given a git revision", func() {
+		it.Before(func() {
+			if _, err := exec.LookPath("git"); err != nil {
+				t.Skip("git is not installed")
+			}
+
+			repo := t.TempDir()
+			git := func(args ...string) {
+				cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+				output, err := cmd.CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(output))
+			}
+
+			copyRevision := func(name string) {
+				for _, file := range []string{"buildpack.toml", "package.toml"} {
+					content, err := os.ReadFile(filepath.Join("testdata", "optional_component_added", name, file))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.WriteFile(filepath.Join(repo, file), content, 0644)).To(Succeed())
+				}
+			}
+
+			git("init", "--quiet")
+			copyRevision("old")
+			git("add", "-A")
+			git("commit", "--quiet", "-m", "old")
+			git("tag", "v1.0.0")
+			copyRevision("new")
+
+			t.Chdir(repo)
+		})
+
+		it("compares the revision with the working tree", func() {
+			code := run([]string{"v1.0.0"}, stdout, stderr)
+			Expect(code).To(Equal(0), stderr.String())
+
+			Expect(stdout.String()).To(ContainSubstring("## Changes from v1.0.0 to the working tree"))
+			Expect(stdout.String()).To(ContainSubstring("Implied version bump: **minor**"))
+			Expect(stdout.String()).To(ContainSubstring("- Added `paketo-buildpacks/opentelemetry` 2.0.0 (optional)"))
+		})
+
+		context("when the revision does not exist", func() {
+			it("exits with an error", func() {
+				code := run([]string{"v0.0.1"}, stdout, stderr)
+				Expect(code).To(Equal(2))
+				Expect(stderr.String()).To(ContainSubstring("failed to read buildpack.toml at v0.0.1"))
+			})
+		})
+	})
+
+	context("when no revisions are given", func() {
+		it("exits with an error", func() {
+			code := run(nil, stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("usage: releasenotes <from> [<to>]"))
+		})
+	})
+}
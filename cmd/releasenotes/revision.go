@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// Member is a component buildpack in an order group.
+type Member struct {
+	ID       string
+	Version  string
+	Optional bool
+}
+
+// Revision is the content of buildpack.toml and package.toml at one point in
+// the history of the composite buildpack.
+type Revision struct {
+	Groups [][]Member
+
+	// Dependencies maps the id of every component buildpack in package.toml
+	// onto its version.
+	Dependencies map[string]string
+}
+
+// LoadRevision reads buildpack.toml and package.toml from source, which is
+// either a directory or a git revision of the repository in the working
+// directory.
+func LoadRevision(source string) (Revision, error) {
+	read := func(name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(source, name))
+	}
+
+	info, err := os.Stat(source)
+	if err != nil || !info.IsDir() {
+		read = func(name string) ([]byte, error) {
+			stderr := bytes.NewBuffer(nil)
+			cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", source, name))
+			cmd.Stderr = stderr
+
+			content, err := cmd.Output()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s at %s: %w: %s", name, source, err, strings.TrimSpace(stderr.String()))
+			}
+			return content, nil
+		}
+	}
+
+	buildpackContent, err := read("buildpack.toml")
+	if err != nil {
+		return Revision{}, err
+	}
+
+	packageContent, err := read("package.toml")
+	if err != nil {
+		return Revision{}, err
+	}
+
+	return ParseRevision(buildpackContent, packageContent)
+}
+
+// ParseRevision parses the content of buildpack.toml and package.toml.
+func ParseRevision(buildpackContent, packageContent []byte) (Revision, error) {
+	var buildpack composite.Buildpack
+	err := toml.Unmarshal(buildpackContent, &buildpack)
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
+	}
+
+	var pkg composite.Package
+	err = toml.Unmarshal(packageContent, &pkg)
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to parse package.toml: %w", err)
+	}
+
+	revision := Revision{Dependencies: map[string]string{}}
+	for _, order := range buildpack.Order {
+		var group []Member
+		for _, member := range order.Group {
+			group = append(group, Member{ID: member.ID, Version: member.Version, Optional: member.Optional})
+		}
+		revision.Groups = append(revision.Groups, group)
+	}
+
+	for _, dependency := range pkg.Dependencies {
+		dependency, err := composite.ParseDependencyURI(dependency.URI)
+		if err != nil {
+			return Revision{}, err
+		}
+		revision.Dependencies[dependency.ID] = dependency.Version
+	}
+
+	return revision, nil
+}
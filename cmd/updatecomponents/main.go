@@ -0,0 +1,107 @@
+// Command updatecomponents updates the component buildpacks of the composite
+// buildpack to their latest versions. It resolves the latest version of every
+// dependency in package.toml, rewrites the versions in both buildpack.toml
+// and package.toml, and reports the semver bump that the updates imply.
+//
+// Usage:
+//
+//	go run ./cmd/updatecomponents [--buildpack-toml buildpack.toml] [--package-toml package.toml]
+//		[--oci-layout <dir> | --registry-url <url>] [--dry-run]
+//
+// By default versions are resolved from the registry of each image. With
+// --oci-layout they are resolved offline from a directory that holds an OCI
+// image layout for each component, named after the last element of its
+// repository. When GITHUB_OUTPUT is set, the implied bump is also written to
+// it as semver_bump, which is empty when nothing was updated.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv("GITHUB_OUTPUT"), os.Stdout, os.Stderr))
+}
+
+func run(args []string, githubOutput string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("updatecomponents", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	buildpackPath := flags.String("buildpack-toml", "buildpack.toml", "path to the buildpack.toml of the composite buildpack")
+	packagePath := flags.String("package-toml", "package.toml", "path to the package.toml of the composite buildpack")
+	ociLayout := flags.String("oci-layout", "", "directory of OCI image layouts to resolve versions from instead of a registry")
+	registryURL := flags.String("registry-url", "", "URL of a registry to resolve versions from instead of the registry of each image")
+	dryRun := flags.Bool("dry-run", false, "report the updates without changing any files")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	if *ociLayout != "" && *registryURL != "" {
+		fmt.Fprintln(stderr, "--oci-layout and --registry-url cannot be used together")
+		return 2
+	}
+
+	var source Source = RegistrySource{URL: *registryURL}
+	if *ociLayout != "" {
+		source = OCILayoutSource{Dir: *ociLayout}
+	}
+
+	dependencies, err := Dependencies(*packagePath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	updates, err := Plan(dependencies, source)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for _, update := range updates {
+		fmt.Fprintf(stdout, "%s: %s -> %s (%s)\n", update.ID, update.From, update.To, update.Bump)
+	}
+
+	bump := ImpliedBump(updates)
+	if len(updates) == 0 {
+		fmt.Fprintln(stdout, "all components are up to date")
+	}
+	fmt.Fprintf(stdout, "semver bump: %s\n", bump)
+
+	if !*dryRun && len(updates) > 0 {
+		err = Apply(*buildpackPath, *packagePath, dependencies, updates)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	if githubOutput != "" {
+		value := ""
+		if bump != composite.BumpNone {
+			value = bump.String()
+		}
+
+		file, err := os.OpenFile(githubOutput, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		defer file.Close()
+
+		_, err = fmt.Fprintf(file, "semver_bump=%s\n", value)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Source lists the tags available for the image of a component buildpack.
+type Source interface {
+	Tags(image Image) ([]string, error)
+}
+
+// Image is the image reference of a component buildpack in package.toml,
+// such as docker.io/paketobuildpacks/node-engine.
+type Image struct {
+	Registry   string
+	Repository string
+}
+
+// Name returns the last element of the repository, such as node-engine.
+func (i Image) Name() string {
+	return path.Base(i.Repository)
+}
+
+// OCILayoutSource reads tags from a directory that holds an OCI image layout
+// for every component buildpack, named after the last element of its
+// repository. Such a directory can be populated with, for example,
+// `skopeo copy docker://docker.io/paketobuildpacks/node-engine:1.2.3 oci:<dir>/node-engine:1.2.3`.
+type OCILayoutSource struct {
+	Dir string
+}
+
+func (s OCILayoutSource) Tags(image Image) ([]string, error) {
+	layout := filepath.Join(s.Dir, image.Name())
+
+	content, err := os.ReadFile(filepath.Join(layout, "oci-layout"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no OCI image layout found for %s at %s", image.Repository, layout)
+		}
+		return nil, err
+	}
+
+	var marker struct {
+		ImageLayoutVersion string `json:"imageLayoutVersion"`
+	}
+	err = json.Unmarshal(content, &marker)
+	if err != nil || marker.ImageLayoutVersion == "" {
+		return nil, fmt.Errorf("%s is not a valid OCI image layout", layout)
+	}
+
+	content, err = os.ReadFile(filepath.Join(layout, "index.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var index struct {
+		Manifests []struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"manifests"`
+	}
+	err = json.Unmarshal(content, &index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the index of %s: %w", layout, err)
+	}
+
+	var tags []string
+	for _, manifest := range index.Manifests {
+		if tag := manifest.Annotations["org.opencontainers.image.ref.name"]; tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// RegistrySource lists tags through the OCI distribution API of the registry
+// that hosts each image, requesting an anonymous token when the registry asks
+// for one.
+type RegistrySource struct {
+	Client *http.Client
+
+	// URL replaces the registry of every image when set, for example to use
+	// a mirror.
+	URL string
+}
+
+func (s RegistrySource) Tags(image Image) ([]string, error) {
+	base := s.URL
+	if base == "" {
+		host := image.Registry
+		if host == "docker.io" {
+			host = "registry-1.docker.io"
+		}
+		base = "https://" + host
+	}
+
+	next := fmt.Sprintf("%s/v2/%s/tags/list", strings.TrimSuffix(base, "/"), image.Repository)
+
+	var (
+		tags  []string
+		token string
+	)
+	for next != "" {
+		response, err := s.get(next, token)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode == http.StatusUnauthorized && token == "" {
+			challenge := response.Header.Get("WWW-Authenticate")
+			response.Body.Close()
+
+			token, err = s.token(challenge)
+			if err != nil {
+				return nil, fmt.Errorf("failed to authenticate with the registry of %s: %w", image.Repository, err)
+			}
+			continue
+		}
+
+		if response.StatusCode != http.StatusOK {
+			response.Body.Close()
+			return nil, fmt.Errorf("failed to list the tags of %s: unexpected status %s", image.Repository, response.Status)
+		}
+
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&page)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the tags of %s: %w", image.Repository, err)
+		}
+		tags = append(tags, page.Tags...)
+
+		next, err = nextPage(next, response.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tags, nil
+}
+
+func (s RegistrySource) get(uri, token string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return client.Do(request)
+}
+
+// token requests an anonymous bearer token as described by the
+// WWW-Authenticate challenge of a registry, such as
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:paketobuildpacks/node-engine:pull"`.
+func (s RegistrySource) token(challenge string) (string, error) {
+	scheme, params, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		values[key] = strings.Trim(value, `"`)
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || values["realm"] == "" {
+		return "", fmt.Errorf("authentication challenge %q has no valid realm", challenge)
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	response, err := s.get(realm.String(), "")
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", response.Status, realm.Redacted())
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&body)
+	if err != nil {
+		return "", err
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// nextPage resolves the URL of the next page of tags from a Link header such
+// as `</v2/paketobuildpacks/node-engine/tags/list?last=1.2.3&n=100>; rel="next"`.
+func nextPage(current, link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+
+	target, _, _ := strings.Cut(link, ";")
+	target = strings.Trim(strings.TrimSpace(target), "<>")
+
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+
+	reference, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header %q: %w", link, err)
+	}
+
+	return base.ResolveReference(reference).String(), nil
+}
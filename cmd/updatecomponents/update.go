@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+)
+
+// Update moves a component buildpack from one version to another.
+type Update struct {
+	ID    string
+	Image Image
+	From  string
+	To    string
+	Bump  composite.Bump
+}
+
+// Dependency is a component buildpack listed in package.toml.
+type Dependency struct {
+	ID      string
+	Image   Image
+	Version string
+	URI     string
+}
+
+// Dependencies returns the component buildpacks listed in the package.toml at
+// path, in the order they are listed.
+func Dependencies(path string) ([]Dependency, error) {
+	pkg, err := composite.DecodePackage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []Dependency
+	for _, d := range pkg.Dependencies {
+		dependency, err := composite.ParseDependencyURI(d.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies = append(dependencies, Dependency{
+			ID:      dependency.ID,
+			Image:   Image{Registry: dependency.Registry, Repository: dependency.Repository},
+			Version: dependency.Version,
+			URI:     dependency.URI,
+		})
+	}
+
+	return dependencies, nil
+}
+
+// Plan resolves the latest version of every dependency from source and
+// returns the updates for the dependencies that are behind it. Tags that are
+// not plain major.minor.patch versions, such as "latest" or pre-releases, are
+// ignored.
+func Plan(dependencies []Dependency, source Source) ([]Update, error) {
+	var updates []Update
+	for _, dependency := range dependencies {
+		current, ok := composite.ParseVersion(dependency.Version)
+		if !ok {
+			return nil, fmt.Errorf("%s: version %q is not a semantic version", dependency.ID, dependency.Version)
+		}
+
+		tags, err := source.Tags(dependency.Image)
+		if err != nil {
+			return nil, err
+		}
+
+		latest, latestTag := current, dependency.Version
+		for _, tag := range tags {
+			if version, ok := composite.ParseVersion(tag); ok && composite.CompareVersions(version, latest) > 0 {
+				latest, latestTag = version, tag
+			}
+		}
+
+		if latestTag == dependency.Version {
+			continue
+		}
+
+		updates = append(updates, Update{
+			ID:    dependency.ID,
+			Image: dependency.Image,
+			From:  dependency.Version,
+			To:    latestTag,
+			Bump:  composite.BumpBetween(current, latest),
+		})
+	}
+
+	return updates, nil
+}
+
+// Apply rewrites the versions of the updated components in buildpack.toml and
+// package.toml. Only the version strings are changed, so the formatting and
+// comments of both files are preserved. Neither file is written when a
+// dependency URI is not found in package.toml as a double-quoted string.
+func Apply(buildpackPath, packagePath string, dependencies []Dependency, updates []Update) error {
+	content, err := os.ReadFile(packagePath)
+	if err != nil {
+		return err
+	}
+
+	pkg := string(content)
+	for _, update := range updates {
+		for _, dependency := range dependencies {
+			if dependency.ID != update.ID {
+				continue
+			}
+
+			uri := strings.TrimSuffix(dependency.URI, ":"+update.From) + ":" + update.To
+			replaced := strings.ReplaceAll(pkg, fmt.Sprintf("%q", dependency.URI), fmt.Sprintf("%q", uri))
+			if replaced == pkg {
+				return fmt.Errorf("%s: found no %q to update to %s", packagePath, dependency.URI, update.To)
+			}
+			pkg = replaced
+		}
+	}
+
+	content, err = os.ReadFile(buildpackPath)
+	if err != nil {
+		return err
+	}
+
+	versions := map[string]Update{}
+	for _, update := range updates {
+		versions[update.ID] = update
+	}
+
+	buildpack, err := rewriteGroupVersions(string(content), versions)
+	if err != nil {
+		return fmt.Errorf("%s: %w", buildpackPath, err)
+	}
+
+	err = os.WriteFile(packagePath, []byte(pkg), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(buildpackPath, []byte(buildpack), 0644)
+}
+
+var (
+	idLine      = regexp.MustCompile(`^\s*id\s*=\s*"([^"]*)"`)
+	versionLine = regexp.MustCompile(`^(\s*version\s*=\s*")([^"]*)(".*)$`)
+)
+
+// rewriteGroupVersions replaces the version of every [[order.group]] entry
+// whose id has an update. The id and version of an entry may appear in any
+// order within its table.
+func rewriteGroupVersions(content string, updates map[string]Update) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	var (
+		inGroup bool
+		id      string
+		version = -1
+	)
+
+	flush := func() error {
+		update, ok := updates[id]
+		if !inGroup || !ok {
+			return nil
+		}
+
+		if version == -1 {
+			return fmt.Errorf("order group entry for %s has no version", id)
+		}
+
+		match := versionLine.FindStringSubmatch(lines[version])
+		if match[2] != update.From {
+			return fmt.Errorf("order group entry for %s has version %s, expected %s", id, match[2], update.From)
+		}
+		lines[version] = match[1] + update.To + match[3]
+
+		return nil
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if err := flush(); err != nil {
+				return "", err
+			}
+			inGroup, id, version = trimmed == "[[order.group]]", "", -1
+			continue
+		}
+
+		if !inGroup {
+			continue
+		}
+
+		if match := idLine.FindStringSubmatch(line); match != nil {
+			id = match[1]
+		} else if versionLine.MatchString(line) {
+			version = i
+		}
+	}
+
+	if err := flush(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ImpliedBump returns the largest bump of any of the updated components,
+// which is the bump the composite buildpack needs.
+func ImpliedBump(updates []Update) composite.Bump {
+	bump := composite.BumpNone
+	for _, update := range updates {
+		bump = max(bump, update.Bump)
+	}
+
+	return bump
+}
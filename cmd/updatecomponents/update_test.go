@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUpdateComponents(t *testing.T) {
+	suite := spec.New("updatecomponents", spec.Report(report.Terminal{}))
+	suite("Run", testRun)
+	suite("OCILayoutSource", testOCILayoutSource)
+	suite("RegistrySource", testRegistrySource)
+	suite("Apply", testApply)
+	suite("RewriteGroupVersions", testRewriteGroupVersions)
+	suite.Run(t)
+}
+
+// writeOCILayout writes a stand-in for an OCI image layout of the named
+// image, as produced by `skopeo copy ... oci:<dir>/<name>:<tag>`, with one
+// manifest for each tag.
+func writeOCILayout(t *testing.T, dir, name string, tags ...string) {
+	t.Helper()
+
+	layout := filepath.Join(dir, name)
+	blobs := filepath.Join(layout, "blobs", "sha256")
+	if err := os.MkdirAll(blobs, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(layout, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	type descriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int               `json:"size"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
+
+	index := struct {
+		SchemaVersion int          `json:"schemaVersion"`
+		Manifests     []descriptor `json:"manifests"`
+	}{SchemaVersion: 2}
+
+	for _, tag := range tags {
+		manifest := fmt.Appendf(nil, `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","annotations":{"stand-in":%q}}`, name+":"+tag)
+		digest := fmt.Sprintf("%x", sha256.Sum256(manifest))
+		if err := os.WriteFile(filepath.Join(blobs, digest), manifest, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		index.Manifests = append(index.Manifests, descriptor{
+			MediaType:   "application/vnd.oci.image.manifest.v1+json",
+			Digest:      "sha256:" + digest,
+			Size:        len(manifest),
+			Annotations: map[string]string{"org.opencontainers.image.ref.name": tag},
+		})
+	}
+
+	content, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(layout, "index.json"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testRun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir           string
+		layouts       string
+		buildpackPath string
+		packagePath   string
+		githubOutput  string
+
+		originalBuildpack string
+		originalPackage   string
+
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+		layouts = filepath.Join(dir, "layouts")
+		buildpackPath = filepath.Join(dir, "buildpack.toml")
+		packagePath = filepath.Join(dir, "package.toml")
+		githubOutput = filepath.Join(dir, "github-output")
+
+		// Work on copies of the files of the repository, so that the test
+		// also covers their formatting.
+		for path, source := range map[string]string{buildpackPath: "buildpack.toml", packagePath: "package.toml"} {
+			content, err := os.ReadFile(filepath.Join("..", "..", source))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(path, content, 0644)).To(Succeed())
+		}
+
+		content, err := os.ReadFile(buildpackPath)
+		Expect(err).NotTo(HaveOccurred())
+		originalBuildpack = string(content)
+
+		content, err = os.ReadFile(packagePath)
+		Expect(err).NotTo(HaveOccurred())
+		originalPackage = string(content)
+
+		dependencies, err := Dependencies(packagePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, dependency := range dependencies {
+			writeOCILayout(t, layouts, dependency.Image.Name(), dependency.Version, "latest")
+		}
+
+		stdout = bytes.NewBuffer(nil)
+		stderr = bytes.NewBuffer(nil)
+	})
+
+	context("when every component is up to date", func() {
+		it("leaves the files untouched and reports no bump", func() {
+			code := run([]string{"--buildpack-toml", buildpackPath, "--package-toml", packagePath, "--oci-layout", layouts}, githubOutput, stdout, stderr)
+			Expect(code).To(Equal(0), stderr.String())
+
+			Expect(stdout.String()).To(Equal("all components are up to date\nsemver bump: none\n"))
+
+			content, err := os.ReadFile(buildpackPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(originalBuildpack))
+
+			content, err = os.ReadFile(githubOutput)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("semver_bump=\n"))
+		})
+	})
+
+	context("when newer versions are available", func() {
+		var dependencies map[string]Dependency
+
+		it.Before(func() {
+			list, err := Dependencies(packagePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			dependencies = map[string]Dependency{}
+			for _, dependency := range list {
+				dependencies[dependency.ID] = dependency
+			}
+
+			nodeEngine := dependencies["paketo-buildpacks/node-engine"]
+			writeOCILayout(t, layouts, "node-engine", nodeEngine.Version, "8.5.10", "8.6.0", "9.0.0-rc.1", "latest")
+
+			procfile := dependencies["paketo-buildpacks/procfile"]
+			writeOCILayout(t, layouts, "procfile", "1.0.0", procfile.Version, "99.0.0")
+		})
+
+		it("updates buildpack.toml and package.toml together and reports the bump", func() {
+			code := run([]string{"--buildpack-toml", buildpackPath, "--package-toml", packagePath, "--oci-layout", layouts}, githubOutput, stdout, stderr)
+			Expect(code).To(Equal(0), stderr.String())
+
+			nodeEngine := dependencies["paketo-buildpacks/node-engine"]
+			procfile := dependencies["paketo-buildpacks/procfile"]
+
+			Expect(stdout.String()).To(Equal(fmt.Sprintf(
+				"paketo-buildpacks/node-engine: %s -> 8.6.0 (minor)\n"+
+					"paketo-buildpacks/procfile: %s -> 99.0.0 (major)\n"+
+					"semver bump: major\n",
+				nodeEngine.Version, procfile.Version)))
+
+			// Only the versions change, everything else is left as it was.
+			expectedPackage := strings.NewReplacer(
+				fmt.Sprintf("%q", nodeEngine.URI), `"docker://docker.io/paketobuildpacks/node-engine:8.6.0"`,
+				fmt.Sprintf("%q", procfile.URI), `"docker://docker.io/paketobuildpacks/procfile:99.0.0"`,
+			).Replace(originalPackage)
+
+			content, err := os.ReadFile(packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(expectedPackage))
+
+			content, err = os.ReadFile(buildpackPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Count(string(content), "\n")).To(Equal(strings.Count(originalBuildpack, "\n")))
+
+			var changed []string
+			original := strings.Split(originalBuildpack, "\n")
+			for i, line := range strings.Split(string(content), "\n") {
+				if line != original[i] {
+					changed = append(changed, strings.TrimSpace(original[i-1])+" "+strings.TrimSpace(line))
+				}
+			}
+
+			Expect(changed).To(ContainElement(`id = "paketo-buildpacks/node-engine" version = "8.6.0"`))
+			Expect(changed).To(ContainElement(`optional = true version = "99.0.0"`))
+			Expect(strings.Count(string(content), `version = "8.6.0"`)).To(Equal(strings.Count(originalBuildpack, fmt.Sprintf(`version = "%s"`, nodeEngine.Version))))
+			Expect(string(content)).NotTo(ContainSubstring(fmt.Sprintf(`version = "%s"`, procfile.Version)))
+
+			content, err = os.ReadFile(githubOutput)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("semver_bump=major\n"))
+		})
+
+		context("when running with --dry-run", func() {
+			it("reports the updates without changing the files", func() {
+				code := run([]string{"--buildpack-toml", buildpackPath, "--package-toml", packagePath, "--oci-layout", layouts, "--dry-run"}, "", stdout, stderr)
+				Expect(code).To(Equal(0), stderr.String())
+
+				Expect(stdout.String()).To(ContainSubstring("semver bump: major"))
+
+				content, err := os.ReadFile(buildpackPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(originalBuildpack))
+
+				content, err = os.ReadFile(packagePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(originalPackage))
+			})
+		})
+	})
+
+	context("when the layout of a component is missing", func() {
+		it.Before(func() {
+			Expect(os.RemoveAll(filepath.Join(layouts, "node-engine"))).To(Succeed())
+		})
+
+		it("exits with an error", func() {
+			code := run([]string{"--buildpack-toml", buildpackPath, "--package-toml", packagePath, "--oci-layout", layouts}, "", stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("no OCI image layout found for paketobuildpacks/node-engine"))
+		})
+	})
+
+	context("when both sources are given", func() {
+		it("exits with an error", func() {
+			code := run([]string{"--oci-layout", layouts, "--registry-url", "https://registry.example.com"}, "", stdout, stderr)
+			Expect(code).To(Equal(2))
+			Expect(stderr.String()).To(ContainSubstring("--oci-layout and --registry-url cannot be used together"))
+		})
+	})
+}
+
+func testOCILayoutSource(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	it("lists the tags of the manifests in the index", func() {
+		writeOCILayout(t, dir, "node-engine", "1.0.0", "1.1.0")
+
+		tags, err := OCILayoutSource{Dir: dir}.Tags(Image{Registry: "docker.io", Repository: "paketobuildpacks/node-engine"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(Equal([]string{"1.0.0", "1.1.0"}))
+	})
+
+	context("when the directory is not an OCI image layout", func() {
+		it("returns an error", func() {
+			Expect(os.MkdirAll(filepath.Join(dir, "node-engine"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "node-engine", "oci-layout"), []byte(`{}`), 0644)).To(Succeed())
+
+			_, err := OCILayoutSource{Dir: dir}.Tags(Image{Registry: "docker.io", Repository: "paketobuildpacks/node-engine"})
+			Expect(err).To(MatchError(ContainSubstring("is not a valid OCI image layout")))
+		})
+	})
+}
+
+func testRegistrySource(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		server *httptest.Server
+	)
+
+	it.Before(func() {
+		mux := http.NewServeMux()
+		server = httptest.NewServer(mux)
+
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("scope") != "repository:paketobuildpacks/node-engine:pull" || r.URL.Query().Get("service") != "registry.example.com" {
+				http.Error(w, "unexpected scope", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"token":"some-token"}`)
+		})
+
+		mux.HandleFunc("/v2/paketobuildpacks/node-engine/tags/list", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer some-token" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.example.com",scope="repository:paketobuildpacks/node-engine:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/paketobuildpacks/node-engine/tags/list?last=1.1.0&n=2>; rel="next"`)
+				fmt.Fprint(w, `{"name":"paketobuildpacks/node-engine","tags":["1.0.0","1.1.0"]}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"name":"paketobuildpacks/node-engine","tags":["2.0.0","latest"]}`)
+		})
+	})
+
+	it.After(func() {
+		server.Close()
+	})
+
+	it("authenticates and lists every page of tags", func() {
+		tags, err := RegistrySource{URL: server.URL}.Tags(Image{Registry: "docker.io", Repository: "paketobuildpacks/node-engine"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(Equal([]string{"1.0.0", "1.1.0", "2.0.0", "latest"}))
+	})
+
+	context("when the repository does not exist", func() {
+		it("returns an error", func() {
+			_, err := RegistrySource{URL: server.URL}.Tags(Image{Registry: "docker.io", Repository: "paketobuildpacks/unknown"})
+			Expect(err).To(MatchError(ContainSubstring("failed to list the tags of paketobuildpacks/unknown: unexpected status 404")))
+		})
+	})
+}
+
+func testApply(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buildpackPath string
+		packagePath   string
+	)
+
+	const buildpack = `[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+`
+
+	it.Before(func() {
+		dir := t.TempDir()
+		buildpackPath = filepath.Join(dir, "buildpack.toml")
+		packagePath = filepath.Join(dir, "package.toml")
+
+		Expect(os.WriteFile(buildpackPath, []byte(buildpack), 0644)).To(Succeed())
+	})
+
+	context("when package.toml does not quote the URI with double quotes", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(packagePath, []byte(`[[dependencies]]
+  uri = 'docker://docker.io/paketobuildpacks/node-engine:1.0.0'
+`), 0644)).To(Succeed())
+		})
+
+		it("returns an error and leaves both files untouched", func() {
+			dependencies, err := Dependencies(packagePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = Apply(buildpackPath, packagePath, dependencies, []Update{{ID: "paketo-buildpacks/node-engine", From: "1.0.0", To: "1.2.0"}})
+			Expect(err).To(MatchError(fmt.Sprintf(`%s: found no "docker://docker.io/paketobuildpacks/node-engine:1.0.0" to update to 1.2.0`, packagePath)))
+
+			content, err := os.ReadFile(buildpackPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(buildpack))
+
+			content, err = os.ReadFile(packagePath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring(":1.0.0'"))
+		})
+	})
+}
+
+func testRewriteGroupVersions(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("rewrites the version wherever it appears in the entry", func() {
+		rewritten, err := rewriteGroupVersions(`[[order]]
+
+  [[order.group]]
+    version = "1.0.0" # pinned by automation
+    id = "paketo-buildpacks/node-engine"
+
+  [[order.group]]
+    id = "paketo-buildpacks/npm-install"
+    version = "1.0.0"
+`, map[string]Update{"paketo-buildpacks/node-engine": {From: "1.0.0", To: "1.2.0"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rewritten).To(Equal(`[[order]]
+
+  [[order.group]]
+    version = "1.2.0" # pinned by automation
+    id = "paketo-buildpacks/node-engine"
+
+  [[order.group]]
+    id = "paketo-buildpacks/npm-install"
+    version = "1.0.0"
+`))
+	})
+
+	context("when buildpack.toml does not match package.toml", func() {
+		it("returns an error", func() {
+			_, err := rewriteGroupVersions(`[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "0.9.0"
+`, map[string]Update{"paketo-buildpacks/node-engine": {From: "1.0.0", To: "1.2.0"}})
+			Expect(err).To(MatchError("order group entry for paketo-buildpacks/node-engine has version 0.9.0, expected 1.0.0"))
+		})
+	})
+}
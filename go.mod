@@ -3,6 +3,7 @@ module github.com/paketo-buildpacks/nodejs
 go 1.26.6
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/onsi/gomega v1.42.1
 	github.com/paketo-buildpacks/occam v0.31.4
 	github.com/sclevine/spec v1.4.0
@@ -11,7 +12,6 @@ require (
 require (
 	dario.cat/mergo v1.0.2 // indirect
 	github.com/Azure/go-ansiterm v0.0.0-20250102033503-faa5f7b0171c // indirect
-	github.com/BurntSushi/toml v1.6.0 // indirect
 	github.com/Microsoft/go-winio v0.6.3-0.20251027160822-ad3df93bed29 // indirect
 	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
@@ -0,0 +1,152 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testBun(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building an app that uses the bun runtime", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "bun"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("should build a working OCI image and run the app with bun", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun Install")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun Start")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Procfile")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Image Labels")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container, "5s").Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("hello world from bun")).OnPort(8080))
+		})
+
+		context("when using optional utility buildpacks", func() {
+			var procfileContainer occam.Container
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(source, "Procfile"), []byte("procfile: echo Procfile command"), 0644)).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(docker.Container.Remove.Execute(procfileContainer.ID)).To(Succeed())
+			})
+
+			it("should build a working OCI image and run the app with the start command from the Procfile and other utility buildpacks", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{
+						"BPE_SOME_VARIABLE":      "some-value",
+						"BP_IMAGE_LABELS":        "some-label=some-value",
+						"BP_LIVE_RELOAD_ENABLED": "true",
+					}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Bun Start")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Procfile")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Image Labels")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Watchexec")))
+
+				environmentVariables, err := image.BuildpackForKey("paketo-buildpacks/environment-variables")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(environmentVariables.Layers["environment-variables"].Metadata["variables"]).To(Equal(map[string]interface{}{"SOME_VARIABLE": "some-value"}))
+
+				Expect(image.Labels["some-label"]).To(Equal("some-value"))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container, "5s").Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring("hello world from bun")).OnPort(8080))
+
+				procfileContainer, err = docker.Container.Run.
+					WithEntrypoint("procfile").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(procfileContainer.ID)
+					return clogs.String()
+				}).Should(ContainSubstring("Procfile command"))
+			})
+		})
+	})
+}
@@ -0,0 +1,240 @@
+// Package compatibility records the results of the integration suite for each
+// builder and target it runs against, and renders them as a compatibility
+// matrix that shows which combinations have been verified.
+package compatibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sclevine/spec"
+)
+
+// Status is the outcome of a spec, or of all the specs of a context.
+type Status string
+
+const (
+	Passed    Status = "passed"
+	Failed    Status = "failed"
+	Skipped   Status = "skipped"
+	NotTested Status = "not tested"
+)
+
+// Result is the outcome of a single spec of the integration suite.
+type Result struct {
+	Builder string `json:"builder"`
+	Target  string `json:"target"`
+	Suite   string `json:"suite"`
+	Context string `json:"context"`
+	Spec    string `json:"spec"`
+	Status  Status `json:"status"`
+}
+
+// Recorder collects the results of the specs of a run of the integration
+// suite against one builder and target.
+type Recorder struct {
+	builder string
+	target  string
+
+	m       sync.Mutex
+	results []Result
+}
+
+func NewRecorder(builder, target string) *Recorder {
+	return &Recorder{
+		builder: NormalizeBuilder(builder),
+		target:  target,
+	}
+}
+
+// Reporter returns a spec.Reporter that records the outcome of every spec of
+// the suite and passes the specs on to next, such as report.Terminal{}. The
+// outcome of a spec is reported once the spec and its After hooks have
+// finished.
+func (r *Recorder) Reporter(next spec.Reporter) spec.Reporter {
+	return reporter{recorder: r, next: next}
+}
+
+type reporter struct {
+	recorder *Recorder
+	next     spec.Reporter
+}
+
+func (r reporter) Start(t *testing.T, plan spec.Plan) {
+	r.next.Start(t, plan)
+}
+
+func (r reporter) Specs(t *testing.T, specs <-chan spec.Spec) {
+	forwarded := make(chan spec.Spec)
+	go func() {
+		defer close(forwarded)
+		for s := range specs {
+			status := Passed
+			switch {
+			case s.Failed:
+				status = Failed
+			case s.Skipped:
+				status = Skipped
+			}
+			r.recorder.Record(s.Text, status)
+
+			forwarded <- s
+		}
+	}()
+
+	r.next.Specs(t, forwarded)
+}
+
+// Record adds the outcome of the spec with the given text, which lists the
+// name of its suite, the text of its enclosing contexts and its own text, such
+// as ["NPM", "when building an app", "builds the app"].
+func (r *Recorder) Record(text []string, status Status) {
+	result := Result{
+		Builder: r.builder,
+		Target:  r.target,
+		Status:  status,
+	}
+	if len(text) > 0 {
+		result.Suite = text[0]
+	}
+	if len(text) > 1 {
+		result.Spec = text[len(text)-1]
+	}
+	if len(text) > 2 {
+		result.Context = strings.Join(text[1:len(text)-1], " / ")
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.results = append(r.results, result)
+}
+
+// Results returns the recorded results, sorted by suite, context and spec.
+func (r *Recorder) Results() []Result {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	results := append([]Result(nil), r.results...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].key() < results[j].key()
+	})
+
+	return results
+}
+
+func (r Result) key() string {
+	return strings.Join([]string{r.Suite, r.Context, r.Spec}, "\x00")
+}
+
+// Save writes the recorded results to a file in dir that is named after the
+// builder and target, replacing the results of an earlier run against the
+// same combination.
+func (r *Recorder) Save(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(r.Results(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(r.builder+"_"+r.target) + ".json"
+
+	return os.WriteFile(filepath.Join(dir, name), content, 0644)
+}
+
+// Load reads the results of every run saved in dir.
+func Load(dir string) ([]Result, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var results []Result
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var run []Result
+		err = json.Unmarshal(content, &run)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		results = append(results, run...)
+	}
+
+	return results, nil
+}
+
+// NormalizeBuilder strips the default registry and the tag from a builder
+// image reference, so that the name reported by pack matches the one listed
+// in integration.json.
+func NormalizeBuilder(builder string) string {
+	for _, prefix := range []string{"index.docker.io/", "docker.io/"} {
+		builder = strings.TrimPrefix(builder, prefix)
+	}
+
+	if i := strings.LastIndex(builder, ":"); i > strings.LastIndex(builder, "/") {
+		builder = builder[:i]
+	}
+
+	return builder
+}
+
+// Builders returns the builders listed in integration.json.
+func Builders(integrationJSONPath string) ([]string, error) {
+	file, err := os.Open(integrationJSONPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var config struct {
+		Builders []string `json:"builders"`
+	}
+	err = json.NewDecoder(file).Decode(&config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", integrationJSONPath, err)
+	}
+
+	var builders []string
+	for _, builder := range config.Builders {
+		builders = append(builders, NormalizeBuilder(builder))
+	}
+
+	return builders, nil
+}
+
+// Targets returns the targets declared in package.toml as os/arch.
+func Targets(packageTOMLPath string) ([]string, error) {
+	var config struct {
+		Targets []struct {
+			OS   string `toml:"os"`
+			Arch string `toml:"arch"`
+		} `toml:"targets"`
+	}
+	_, err := toml.DecodeFile(packageTOMLPath, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", packageTOMLPath, err)
+	}
+
+	var targets []string
+	for _, target := range config.Targets {
+		targets = append(targets, fmt.Sprintf("%s/%s", target.OS, target.Arch))
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,206 @@
+package compatibility_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/nodejs/integration/compatibility"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompatibility(t *testing.T) {
+	suite := spec.New("compatibility", spec.Report(report.Terminal{}))
+	suite("Matrix", testMatrix)
+	suite("Files", testFiles)
+	suite.Run(t)
+}
+
+// TestRecorder runs a suite shaped like the integration suite, with the
+// recorder reporting its specs, and checks what was recorded.
+func TestRecorder(t *testing.T) {
+	Expect := NewWithT(t).Expect
+
+	recorder := compatibility.NewRecorder("index.docker.io/paketobuildpacks/builder-jammy-buildpackless-base:latest", "linux/amd64")
+
+	suite := spec.New("Integration", spec.Parallel(), spec.Report(recorder.Reporter(report.Log{})))
+	suite("NPM", func(t *testing.T, context spec.G, it spec.S) {
+		context("when building an app", func() {
+			it("builds the app", func() {})
+
+			context("with a start script", func() {
+				it("runs the start script", func() {})
+			})
+		})
+
+		context("when BP_OPENTELEMETRY_ENABLED is set", func() {
+			it("keeps node_modules", func() {})
+			it("keeps node_modules", func() {})
+		})
+
+		it("is skipped", func() {
+			t.Skip("not supported")
+		})
+	})
+	suite.Run(t)
+
+	result := func(context, spec string, status compatibility.Status) compatibility.Result {
+		return compatibility.Result{
+			Builder: "paketobuildpacks/builder-jammy-buildpackless-base",
+			Target:  "linux/amd64",
+			Suite:   "NPM",
+			Context: context,
+			Spec:    spec,
+			Status:  status,
+		}
+	}
+
+	Expect(recorder.Results()).To(Equal([]compatibility.Result{
+		result("", "is skipped", compatibility.Skipped),
+		result("when BP_OPENTELEMETRY_ENABLED is set", "keeps node_modules", compatibility.Passed),
+		result("when BP_OPENTELEMETRY_ENABLED is set", "keeps node_modules", compatibility.Passed),
+		result("when building an app", "builds the app", compatibility.Passed),
+		result("when building an app / with a start script", "runs the start script", compatibility.Passed),
+	}))
+}
+
+func testMatrix(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		builders = []string{"paketobuildpacks/builder-jammy-buildpackless-base", "paketobuildpacks/ubi-9-builder-buildpackless"}
+		targets  = []string{"linux/amd64", "linux/arm64"}
+
+		results []compatibility.Result
+	)
+
+	it.Before(func() {
+		results = []compatibility.Result{
+			{Builder: builders[0], Target: "linux/amd64", Suite: "NPM", Context: "when building an app", Spec: "builds", Status: compatibility.Passed},
+			{Builder: builders[0], Target: "linux/amd64", Suite: "NPM", Context: "when building an app", Spec: "runs", Status: compatibility.Passed},
+			{Builder: builders[0], Target: "linux/amd64", Suite: "Bun", Context: "when building an app", Spec: "builds", Status: compatibility.Skipped},
+			{Builder: builders[1], Target: "linux/amd64", Suite: "NPM", Context: "when building an app", Spec: "builds", Status: compatibility.Passed},
+			{Builder: builders[1], Target: "linux/amd64", Suite: "NPM", Context: "when building an app", Spec: "runs", Status: compatibility.Failed},
+			{Builder: builders[1], Target: "linux/amd64", Suite: "Bun", Context: "when building an app", Spec: "builds", Status: compatibility.Passed},
+		}
+	})
+
+	it("aggregates the results for each builder and target", func() {
+		matrix := compatibility.NewMatrix(builders, targets, results)
+
+		Expect(matrix.Summary).To(Equal([]compatibility.Cell{
+			{Builder: builders[0], Target: "linux/amd64", Status: compatibility.Passed, Passed: 2, Skipped: 1},
+			{Builder: builders[1], Target: "linux/amd64", Status: compatibility.Failed, Passed: 2, Failed: 1},
+			{Builder: builders[0], Target: "linux/arm64", Status: compatibility.NotTested},
+			{Builder: builders[1], Target: "linux/arm64", Status: compatibility.NotTested},
+		}))
+
+		Expect(matrix.Rows).To(HaveLen(2))
+		Expect(matrix.Rows[0].Suite).To(Equal("Bun"))
+		Expect(matrix.Rows[0].Cells[0].Status).To(Equal(compatibility.Skipped))
+		Expect(matrix.Rows[1].Suite).To(Equal("NPM"))
+		Expect(matrix.Rows[1].Cells[1].Status).To(Equal(compatibility.Failed))
+	})
+
+	it("renders the matrix as markdown", func() {
+		Expect(compatibility.NewMatrix(builders, targets, results).Markdown()).To(Equal(`## Compatibility matrix
+
+| Builder | linux/amd64 | linux/arm64 |
+| --- | --- | --- |
+| ` + "`paketobuildpacks/builder-jammy-buildpackless-base`" + ` | passed (2, 1 skipped) | not tested |
+| ` + "`paketobuildpacks/ubi-9-builder-buildpackless`" + ` | failed (1 of 3) | not tested |
+
+### linux/amd64
+
+| Suite | Context | builder-jammy-buildpackless-base | ubi-9-builder-buildpackless |
+| --- | --- | --- | --- |
+| Bun | when building an app | skipped | passed (1) |
+| NPM | when building an app | passed (2) | failed (1 of 2) |
+
+### linux/arm64
+
+No builders were tested on this target.
+`))
+	})
+
+	context("when the results include a builder that is not configured", func() {
+		it("adds the builder to the matrix", func() {
+			results = append(results, compatibility.Result{Builder: "some-org/some-builder", Target: "linux/amd64", Suite: "NPM", Spec: "builds", Status: compatibility.Passed})
+
+			matrix := compatibility.NewMatrix(builders, targets, results)
+			Expect(matrix.Builders).To(Equal(append(builders, "some-org/some-builder")))
+		})
+	})
+}
+
+func testFiles(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+	})
+
+	it("saves and loads the results of several runs", func() {
+		jammy := compatibility.NewRecorder("index.docker.io/paketobuildpacks/builder-jammy-buildpackless-base", "linux/amd64")
+		jammy.Record([]string{"NPM", "when building", "builds"}, compatibility.Passed)
+		Expect(jammy.Save(dir)).To(Succeed())
+
+		noble := compatibility.NewRecorder("index.docker.io/paketobuildpacks/ubuntu-noble-builder-buildpackless:0.0.1", "linux/amd64")
+		noble.Record([]string{"NPM", "when building", "builds"}, compatibility.Failed)
+		Expect(noble.Save(dir)).To(Succeed())
+
+		// A second run against the same builder replaces the first.
+		noble = compatibility.NewRecorder("index.docker.io/paketobuildpacks/ubuntu-noble-builder-buildpackless:0.0.2", "linux/amd64")
+		noble.Record([]string{"NPM", "when building", "builds"}, compatibility.Passed)
+		Expect(noble.Save(dir)).To(Succeed())
+
+		results, err := compatibility.Load(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(ConsistOf(
+			compatibility.Result{Builder: "paketobuildpacks/builder-jammy-buildpackless-base", Target: "linux/amd64", Suite: "NPM", Context: "when building", Spec: "builds", Status: compatibility.Passed},
+			compatibility.Result{Builder: "paketobuildpacks/ubuntu-noble-builder-buildpackless", Target: "linux/amd64", Suite: "NPM", Context: "when building", Spec: "builds", Status: compatibility.Passed},
+		))
+	})
+
+	it("writes the matrix as markdown and JSON", func() {
+		matrix := compatibility.NewMatrix([]string{"some-org/some-builder"}, []string{"linux/amd64"}, nil)
+		Expect(matrix.Write(dir)).To(Succeed())
+
+		content, err := os.ReadFile(filepath.Join(dir, "compatibility-matrix.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal(matrix.Markdown()))
+
+		content, err = os.ReadFile(filepath.Join(dir, "compatibility-matrix.json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var decoded compatibility.Matrix
+		Expect(json.Unmarshal(content, &decoded)).To(Succeed())
+		Expect(decoded.Summary).To(Equal([]compatibility.Cell{
+			{Builder: "some-org/some-builder", Target: "linux/amd64", Status: compatibility.NotTested},
+		}))
+	})
+
+	it("reads the builders and targets of the repository", func() {
+		builders, err := compatibility.Builders(filepath.Join("..", "..", "integration.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(builders).To(ContainElements(
+			"paketobuildpacks/ubi-9-builder-buildpackless",
+			"paketobuildpacks/builder-ubi8-buildpackless-base",
+			"paketobuildpacks/builder-jammy-buildpackless-base",
+			"paketobuildpacks/ubuntu-noble-builder-buildpackless",
+			"paketobuildpacks/ubuntu-resolute-builder-buildpackless",
+		))
+
+		targets, err := compatibility.Targets(filepath.Join("..", "..", "package.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(Equal([]string{"linux/amd64", "linux/arm64"}))
+	})
+}
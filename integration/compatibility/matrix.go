@@ -0,0 +1,201 @@
+package compatibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Cell aggregates the results of a group of specs for one builder and
+// target.
+type Cell struct {
+	Builder string `json:"builder"`
+	Target  string `json:"target"`
+	Status  Status `json:"status"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	Skipped int    `json:"skipped"`
+}
+
+func (c *Cell) add(status Status) {
+	switch status {
+	case Passed:
+		c.Passed++
+	case Failed:
+		c.Failed++
+	case Skipped:
+		c.Skipped++
+	}
+
+	switch {
+	case c.Failed > 0:
+		c.Status = Failed
+	case c.Passed > 0:
+		c.Status = Passed
+	case c.Skipped > 0:
+		c.Status = Skipped
+	}
+}
+
+func (c Cell) String() string {
+	total := c.Passed + c.Failed + c.Skipped
+	switch c.Status {
+	case Failed:
+		return fmt.Sprintf("failed (%d of %d)", c.Failed, total)
+	case Passed:
+		if c.Skipped > 0 {
+			return fmt.Sprintf("passed (%d, %d skipped)", c.Passed, c.Skipped)
+		}
+		return fmt.Sprintf("passed (%d)", c.Passed)
+	default:
+		return string(c.Status)
+	}
+}
+
+// Row lists the results of the specs of a suite and context for every
+// builder and target.
+type Row struct {
+	Suite   string `json:"suite"`
+	Context string `json:"context"`
+	Cells   []Cell `json:"cells"`
+}
+
+// Matrix is the compatibility matrix of the composite buildpack.
+type Matrix struct {
+	Builders []string `json:"builders"`
+	Targets  []string `json:"targets"`
+
+	// Summary aggregates all specs for each builder and target.
+	Summary []Cell `json:"summary"`
+	Rows    []Row  `json:"rows"`
+}
+
+// NewMatrix arranges results by suite and context for every combination of
+// the builders and targets. Combinations without results are reported as not
+// tested, and builders or targets that only appear in the results are added
+// to the matrix.
+func NewMatrix(builders, targets []string, results []Result) Matrix {
+	matrix := Matrix{
+		Builders: slices.Clone(builders),
+		Targets:  slices.Clone(targets),
+	}
+
+	for _, result := range results {
+		if !slices.Contains(matrix.Builders, result.Builder) {
+			matrix.Builders = append(matrix.Builders, result.Builder)
+		}
+		if !slices.Contains(matrix.Targets, result.Target) {
+			matrix.Targets = append(matrix.Targets, result.Target)
+		}
+	}
+
+	cells := func() []Cell {
+		var cells []Cell
+		for _, target := range matrix.Targets {
+			for _, builder := range matrix.Builders {
+				cells = append(cells, Cell{Builder: builder, Target: target, Status: NotTested})
+			}
+		}
+		return cells
+	}
+
+	index := func(result Result) int {
+		return slices.Index(matrix.Targets, result.Target)*len(matrix.Builders) + slices.Index(matrix.Builders, result.Builder)
+	}
+
+	matrix.Summary = cells()
+
+	rows := map[string]int{}
+	for _, result := range results {
+		key := result.Suite + "\x00" + result.Context
+		i, ok := rows[key]
+		if !ok {
+			i = len(matrix.Rows)
+			rows[key] = i
+			matrix.Rows = append(matrix.Rows, Row{Suite: result.Suite, Context: result.Context, Cells: cells()})
+		}
+
+		matrix.Rows[i].Cells[index(result)].add(result.Status)
+		matrix.Summary[index(result)].add(result.Status)
+	}
+
+	slices.SortStableFunc(matrix.Rows, func(a, b Row) int {
+		return strings.Compare(a.Suite+"\x00"+a.Context, b.Suite+"\x00"+b.Context)
+	})
+
+	return matrix
+}
+
+// Markdown renders a summary table of every builder and target, followed by
+// a table of the results of each suite and context for every target that
+// was tested.
+func (m Matrix) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## Compatibility matrix\n\n")
+	b.WriteString("| Builder | " + strings.Join(m.Targets, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(m.Targets)+1) + "\n")
+	for i, builder := range m.Builders {
+		var cells []string
+		for j := range m.Targets {
+			cells = append(cells, m.Summary[j*len(m.Builders)+i].String())
+		}
+		fmt.Fprintf(&b, "| `%s` | %s |\n", builder, strings.Join(cells, " | "))
+	}
+
+	for j, target := range m.Targets {
+		fmt.Fprintf(&b, "\n### %s\n\n", target)
+
+		tested := false
+		for i := range m.Builders {
+			if m.Summary[j*len(m.Builders)+i].Status != NotTested {
+				tested = true
+			}
+		}
+		if !tested {
+			b.WriteString("No builders were tested on this target.\n")
+			continue
+		}
+
+		var names []string
+		for _, builder := range m.Builders {
+			names = append(names, builder[strings.LastIndex(builder, "/")+1:])
+		}
+
+		b.WriteString("| Suite | Context | " + strings.Join(names, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(m.Builders)+2) + "\n")
+		for _, row := range m.Rows {
+			var cells []string
+			for i := range m.Builders {
+				cells = append(cells, row.Cells[j*len(m.Builders)+i].String())
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", row.Suite, row.Context, strings.Join(cells, " | "))
+		}
+	}
+
+	return b.String()
+}
+
+// Write renders the matrix into compatibility-matrix.md and
+// compatibility-matrix.json in dir.
+func (m Matrix) Write(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "compatibility-matrix.json"), append(content, '\n'), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "compatibility-matrix.md"), []byte(m.Markdown()), 0644)
+}
@@ -0,0 +1,142 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testDeno(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a deno app with a lockfile and a start task", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "deno"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("should build a working OCI image that caches dependencies and runs the start task", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Deno")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Deno Install")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Deno Start")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Start")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Procfile")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Image Labels")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container, "5s").Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("hello world from deno")).OnPort(8080))
+		})
+	})
+
+	context("when building a deno app without a lockfile", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "deno_no_lockfile"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("should build a working OCI image that runs the start task without caching dependencies", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Deno")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Deno Start")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Deno Install")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Start")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container, "5s").Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("hello world from deno")).OnPort(8080))
+		})
+	})
+}
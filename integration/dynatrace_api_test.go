@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+const dynatraceAgentPath = "/v1/deployment/installer/agent/unix/paas/latest"
+
+// dynatraceAPI is a stand-in for the deployment API of a Dynatrace
+// environment. It serves a OneAgent PaaS archive whose Node.js loader only
+// marks itself as loaded, and rejects requests that do not carry the
+// expected API token.
+type dynatraceAPI struct {
+	server *httptest.Server
+	token  string
+	agent  []byte
+}
+
+func newDynatraceAPI(token string) (*dynatraceAPI, error) {
+	agent, err := dynatraceAgentArchive()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := listenOnDockerBridge()
+	if err != nil {
+		return nil, err
+	}
+
+	api := &dynatraceAPI{
+		token: token,
+		agent: agent,
+	}
+	api.server = httptest.NewUnstartedServer(http.HandlerFunc(api.serveHTTP))
+	api.server.Listener = listener
+	api.server.Start()
+
+	return api, nil
+}
+
+// URL returns the API URL of the environment as seen from inside a container
+// attached to the default docker bridge network.
+func (a *dynatraceAPI) URL() string {
+	return fmt.Sprintf("%s/api", a.server.URL)
+}
+
+func (a *dynatraceAPI) Close() {
+	a.server.Close()
+}
+
+func (a *dynatraceAPI) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") != fmt.Sprintf("Api-Token %s", a.token) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch req.URL.Path {
+	case "/api" + dynatraceAgentPath + "/metainfo":
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"latestAgentVersion": "1.0.0"})
+
+	case "/api" + dynatraceAgentPath:
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(a.agent)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// dynatraceAgentArchive builds the OneAgent PaaS archive. The Node.js loader
+// does not depend on the architecture, so the manifest lists it for both of
+// the platforms that the buildpack is packaged for.
+func dynatraceAgentArchive() ([]byte, error) {
+	loader := []map[string]string{
+		{"path": "agent/bin/any/onenodeloader.js", "binarytype": "primary"},
+	}
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"version": "1.0.0",
+		"technologies": map[string]interface{}{
+			"nodejs": map[string]interface{}{
+				"linux-x86-64": loader,
+				"linux-arm64":  loader,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"manifest.json", manifest},
+		{"agent/bin/any/onenodeloader.js", []byte("global.__dynatraceLoaded = true\n")},
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buffer)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = w.Write(file.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testGitDependency(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a node app that depends on a git repository", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name     string
+			source   string
+			revision string
+		)
+
+		git := func(dir string, args ...string) string {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=integration", "-c", "user.email=integration@example.com"}, args...)...)
+			cmd.Dir = dir
+			output, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			return strings.TrimSpace(string(output))
+		}
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "git_dependency"))
+			Expect(err).NotTo(HaveOccurred())
+
+			greeting := filepath.Join(source, "greeting")
+			git(greeting, "init", "--initial-branch", "main")
+			git(greeting, "add", ".")
+			git(greeting, "commit", "--message", "greeting")
+			revision = git(greeting, "rev-parse", "HEAD")
+
+			for _, app := range []string{"npm", "yarn"} {
+				git(source, "clone", "--bare", greeting, filepath.Join(app, "greeting.git"))
+			}
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the app uses npm", func() {
+			it("installs the git dependency and uses it at runtime", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "npm"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Git CLI")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring("Hello from a git dependency!")).OnPort(8080))
+			})
+		})
+
+		context("when the app uses yarn", func() {
+			it.Before(func() {
+				lockfile := fmt.Sprintf(`# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"greeting@git+file:///workspace/greeting.git#main":
+  version "0.0.0"
+  resolved "git+file:///workspace/greeting.git#%s"
+`, revision)
+				Expect(os.WriteFile(filepath.Join(source, "yarn", "yarn.lock"), []byte(lockfile), 0644)).To(Succeed())
+			})
+
+			it("installs the git dependency and uses it at runtime", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "yarn"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Git CLI")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring("Hello from a git dependency!")).OnPort(8080))
+			})
+		})
+	})
+}
@@ -0,0 +1,171 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testHealthChecker(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building an app with the health checker enabled", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		// healthCheck runs the health-check process of the image inside of the
+		// running app container, with any additional environment variables.
+		healthCheck := func(env ...string) error {
+			args := []string{"exec"}
+			for _, variable := range env {
+				args = append(args, "--env", variable)
+			}
+			args = append(args, container.ID, "/cnb/process/health-check")
+
+			output, err := exec.Command("docker", args...).CombinedOutput()
+			if err != nil {
+				return fmt.Errorf("health check failed: %w: %s", err, output)
+			}
+
+			return nil
+		}
+
+		build := func() fmt.Stringer {
+			server, err := os.ReadFile(filepath.Join("testdata", "health_checker", "server.js"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(source, "server.js"), server, 0644)).To(Succeed())
+
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				WithEnv(map[string]string{
+					"BP_HEALTH_CHECKER_ENABLED": "true",
+					"BPE_DEFAULT_THC_PATH":      "/healthz",
+					"BPE_DEFAULT_THC_PORT":      "8080",
+				}).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Health Checker")))
+
+			var metadata struct {
+				Processes []struct {
+					Type string `json:"type"`
+				} `json:"processes"`
+			}
+			Expect(json.Unmarshal([]byte(image.Labels["io.buildpacks.build.metadata"]), &metadata)).To(Succeed())
+			Expect(metadata.Processes).To(ContainElement(HaveField("Type", "health-check")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(BeAvailable())
+
+			return logs
+		}
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the app does not use a package manager", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "no_package_manager"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("passes the health check against the configured path", func() {
+				logs := build()
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Start")))
+
+				Eventually(func() error { return healthCheck() }).Should(Succeed())
+
+				Expect(healthCheck("THC_PATH=/")).NotTo(Succeed())
+				Expect(healthCheck("THC_PORT=9999")).NotTo(Succeed())
+			})
+		})
+
+		context("when the app uses npm", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "npm"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("passes the health check against the configured path", func() {
+				logs := build()
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+				Eventually(func() error { return healthCheck() }).Should(Succeed())
+
+				Expect(healthCheck("THC_PATH=/")).NotTo(Succeed())
+				Expect(healthCheck("THC_PORT=9999")).NotTo(Succeed())
+			})
+		})
+
+		context("when the app uses yarn", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "yarn"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("passes the health check against the configured path", func() {
+				logs := build()
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+
+				Eventually(func() error { return healthCheck() }).Should(Succeed())
+
+				Expect(healthCheck("THC_PATH=/")).NotTo(Succeed())
+				Expect(healthCheck("THC_PORT=9999")).NotTo(Succeed())
+			})
+		})
+	})
+}
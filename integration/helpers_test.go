@@ -0,0 +1,132 @@
+package integration_test
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+
+	. "github.com/onsi/gomega"
+)
+
+// listenOnDockerBridge listens on a free port of the address of the host on
+// the default docker bridge network, so that stand-in servers on the host can
+// be reached from containers without listening on every interface.
+func listenOnDockerBridge() (net.Listener, error) {
+	output, err := exec.Command("docker", "network", "inspect", "bridge", "--format", "{{(index .IPAM.Config 0).Gateway}}").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the docker bridge gateway: %w: %s", err, output)
+	}
+
+	return net.Listen("tcp", net.JoinHostPort(strings.TrimSpace(string(output)), "0"))
+}
+
+// imagePlatform returns the os/arch of a local image, such as linux/amd64.
+func imagePlatform(image string) (string, error) {
+	output, err := exec.Command("docker", "image", "inspect", image, "--format", "{{.Os}}/{{.Architecture}}").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect the platform of %s: %w: %s", image, err, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// apmBindings is a copy of the apm_bindings fixture for a single build, whose
+// Dynatrace binding points at a local stand-in for the Dynatrace API.
+type apmBindings struct {
+	dir       string
+	dynatrace *dynatraceAPI
+}
+
+func newAPMBindings() (*apmBindings, error) {
+	dir, err := occam.Source(filepath.Join("testdata", "apm_bindings"))
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := os.ReadFile(filepath.Join(dir, "dynatrace", "api-token"))
+	if err != nil {
+		return nil, err
+	}
+
+	dynatrace, err := newDynatraceAPI(string(token))
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := &apmBindings{dir: dir, dynatrace: dynatrace}
+
+	err = os.WriteFile(filepath.Join(dir, "dynatrace", "api-url"), []byte(dynatrace.URL()), 0644)
+	if err != nil {
+		return nil, fmt.Errorf("%w (cleanup: %v)", err, bindings.Close())
+	}
+
+	return bindings, nil
+}
+
+// Volumes mounts the bindings under /bindings, for use with
+// SERVICE_BINDING_ROOT=/bindings.
+func (b *apmBindings) Volumes() []string {
+	return []string{
+		fmt.Sprintf("%s/new-relic:/bindings/new-relic", b.dir),
+		fmt.Sprintf("%s/dynatrace:/bindings/dynatrace", b.dir),
+	}
+}
+
+func (b *apmBindings) Close() error {
+	b.dynatrace.Close()
+	return os.RemoveAll(b.dir)
+}
+
+// ExpectAgentsLoaded checks that an image built with BP_DATADOG_ENABLED and
+// these bindings requires the Datadog agent through NODE_OPTIONS, and the New
+// Relic and Dynatrace agents as well when the bindings are mounted at launch.
+func (b *apmBindings) ExpectAgentsLoaded(t *testing.T, docker occam.Docker, imageID string) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+	)
+
+	datadogContainer, err := docker.Container.Run.
+		WithEntrypoint("launcher").
+		WithCommand(`node -e 'console.log("NODE_OPTIONS=" + process.env.NODE_OPTIONS); console.log("dd-trace loaded: " + (global._ddtrace !== undefined))'`).
+		Execute(imageID)
+	Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		Expect(docker.Container.Remove.Execute(datadogContainer.ID)).To(Succeed())
+	}()
+
+	Eventually(func() string {
+		clogs, _ := docker.Container.Logs.Execute(datadogContainer.ID)
+		return clogs.String()
+	}).Should(And(
+		MatchRegexp(`NODE_OPTIONS=.*--require \S*dd-trace`),
+		ContainSubstring("dd-trace loaded: true"),
+	))
+
+	apmContainer, err := docker.Container.Run.
+		WithEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+		WithVolumes(b.Volumes()...).
+		WithEntrypoint("launcher").
+		WithCommand(`node -e 'console.log("NODE_OPTIONS=" + process.env.NODE_OPTIONS); console.log("newrelic loaded: " + Object.keys(require.cache).some((m) => m.includes("/newrelic/"))); console.log("dynatrace loaded: " + (global.__dynatraceLoaded === true))'`).
+		Execute(imageID)
+	Expect(err).NotTo(HaveOccurred())
+	defer func() {
+		Expect(docker.Container.Remove.Execute(apmContainer.ID)).To(Succeed())
+	}()
+
+	Eventually(func() string {
+		clogs, _ := docker.Container.Logs.Execute(apmContainer.ID)
+		return clogs.String()
+	}).Should(And(
+		MatchRegexp(`NODE_OPTIONS=.*--require \S*newrelic`),
+		MatchRegexp(`NODE_OPTIONS=.*--require \S*onenodeloader\.js`),
+		ContainSubstring("newrelic loaded: true"),
+		ContainSubstring("dynatrace loaded: true"),
+	))
+}
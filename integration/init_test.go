@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/paketo-buildpacks/nodejs/integration/compatibility"
 	"github.com/paketo-buildpacks/occam"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -55,10 +56,51 @@ func TestIntegration(t *testing.T) {
 
 	SetDefaultEventuallyTimeout(10 * time.Second)
 
-	suite := spec.New("Integration", spec.Parallel(), spec.Report(report.Terminal{}))
+	// The result of every spec is recorded for the builder of this run and the
+	// platform of its image, which is the target the apps are built for. The
+	// results are saved to build/compatibility, where the results of runs
+	// against other builders and targets can be collected, and rendered into a
+	// compatibility matrix in the build directory once all specs have
+	// finished.
+	target, err := imagePlatform(builder.BuilderName)
+	Expect(err).NotTo(HaveOccurred())
+
+	recorder := compatibility.NewRecorder(builder.BuilderName, target)
+	t.Cleanup(func() {
+		resultsDir := filepath.Join("..", "build", "compatibility")
+		Expect(recorder.Save(resultsDir)).To(Succeed())
+
+		results, err := compatibility.Load(resultsDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		builders, err := compatibility.Builders("../integration.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		targets, err := compatibility.Targets("../package.toml")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(compatibility.NewMatrix(builders, targets, results).Write(filepath.Join("..", "build"))).To(Succeed())
+	})
+
+	suite := spec.New("Integration", spec.Parallel(), spec.Report(recorder.Reporter(report.Terminal{})))
+	suite("Bun", testBun)
+	suite("Deno", testDeno)
+	suite("GitDependency", testGitDependency)
+	suite("HealthChecker", testHealthChecker)
+	suite("NextJS", testNextJS)
 	suite("NodeStart", testNodeStart)
 	suite("NPM", testNPM)
+	suite("Offline", testOffline)
+	suite("OpenTelemetry", testOpenTelemetry)
+	suite("PackageManager", testPackageManager)
+	suite("PNPM", testPNPM)
+	suite("PrivateRegistry", testPrivateRegistry)
+	suite("Puppeteer", testPuppeteer)
 	suite("ReproducibleBuilds", testReproducibleBuilds)
+	suite("StaticFrontend", testStaticFrontend)
+	suite("TypeScript", testTypeScript)
+	suite("Workspaces", testWorkspaces)
 	suite("Yarn", testYarn)
+	suite("YarnBerry", testYarnBerry)
 	suite.Run(t)
 }
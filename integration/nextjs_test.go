@@ -0,0 +1,159 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testNextJS(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a next.js app with standalone output", func() {
+		var (
+			image        occam.Image
+			defaultImage occam.Image
+			container    occam.Container
+
+			name        string
+			defaultName string
+			source      string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			defaultName, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "nextjs"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(defaultImage.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(defaultName))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("launches the standalone server and ships less at launch than the default npm path", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Next.js")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+			image, err = docker.Image.Inspect.Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			var metadata struct {
+				Processes []struct {
+					Type    string   `json:"type"`
+					Command []string `json:"command"`
+					Args    []string `json:"args"`
+				} `json:"processes"`
+			}
+			Expect(json.Unmarshal([]byte(image.Labels["io.buildpacks.build.metadata"]), &metadata)).To(Succeed())
+
+			var web []string
+			for _, process := range metadata.Processes {
+				if process.Type == "web" {
+					web = append(append([]string{}, process.Command...), process.Args...)
+				}
+			}
+			Expect(strings.Join(web, " ")).To(Equal("node .next/standalone/server.js"))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{
+					"PORT":     "8080",
+					"HOSTNAME": "0.0.0.0",
+				}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container, "10s").Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("Hello from Next.js standalone")).OnPort(8080))
+
+			defaultImage, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				WithEnv(map[string]string{
+					"BP_NEXTJS_STANDALONE_ENABLED": "false",
+					"BP_NODE_RUN_SCRIPTS":          "build",
+				}).
+				Execute(defaultName, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+			// launchSize is the size in kilobytes of the app and the layers that
+			// are available at launch.
+			launchSize := func(id string) int64 {
+				sizeContainer, err := docker.Container.Run.
+					WithEntrypoint("launcher").
+					WithCommand("echo launch-size=$(du -skc /workspace /layers | tail -n 1 | cut -f 1)").
+					Execute(id)
+				Expect(err).NotTo(HaveOccurred())
+				defer func() {
+					Expect(docker.Container.Remove.Execute(sizeContainer.ID)).To(Succeed())
+				}()
+
+				var match []string
+				Eventually(func() []string {
+					clogs, _ := docker.Container.Logs.Execute(sizeContainer.ID)
+					match = regexp.MustCompile(`launch-size=(\d+)`).FindStringSubmatch(clogs.String())
+					return match
+				}).Should(HaveLen(2))
+
+				size, err := strconv.ParseInt(match[1], 10, 64)
+				Expect(err).NotTo(HaveOccurred())
+
+				return size
+			}
+
+			Expect(launchSize(image.ID)).To(BeNumerically("<", launchSize(defaultImage.ID)))
+		})
+	})
+}
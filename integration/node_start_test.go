@@ -89,13 +89,22 @@ func testNodeStart(t *testing.T, context spec.G, it spec.S) {
 		})
 
 		context("when using optional utility buildpacks", func() {
-			var procfileContainer occam.Container
+			var (
+				procfileContainer occam.Container
+				bindings          *apmBindings
+			)
+
 			it.Before(func() {
 				Expect(os.WriteFile(filepath.Join(source, "Procfile"), []byte("procfile: echo Procfile command"), 0644)).To(Succeed())
+
+				var err error
+				bindings, err = newAPMBindings()
+				Expect(err).NotTo(HaveOccurred())
 			})
 
 			it.After(func() {
 				Expect(docker.Container.Remove.Execute(procfileContainer.ID)).To(Succeed())
+				Expect(bindings.Close()).To(Succeed())
 			})
 
 			it("should build a working OCI image and run the app with the start command from the Procfile and other utility buildpacks", func() {
@@ -110,7 +119,9 @@ func testNodeStart(t *testing.T, context spec.G, it spec.S) {
 						"BP_IMAGE_LABELS":        "some-label=some-value",
 						"BP_LIVE_RELOAD_ENABLED": "true",
 						"BP_DATADOG_ENABLED":     "true",
+						"SERVICE_BINDING_ROOT":   "/bindings",
 					}).
+					WithVolumes(bindings.Volumes()...).
 					Execute(name, source)
 				Expect(err).NotTo(HaveOccurred())
 
@@ -119,6 +130,9 @@ func testNodeStart(t *testing.T, context spec.G, it spec.S) {
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Procfile")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Image Labels")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Datadog")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for New Relic")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Dynatrace")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Watchexec")))
 
 				environmentVariables, err := image.BuildpackForKey("paketo-buildpacks/environment-variables")
@@ -146,6 +160,8 @@ func testNodeStart(t *testing.T, context spec.G, it spec.S) {
 					clogs, _ := docker.Container.Logs.Execute(procfileContainer.ID)
 					return clogs.String()
 				}).Should(ContainSubstring("Procfile command"))
+
+				bindings.ExpectAgentsLoaded(t, docker, image.ID)
 			})
 		})
 
@@ -273,4 +289,61 @@ func testNodeStart(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("when building a vendored node app with a native addon", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "native_addon"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("rebuilds the addon for the stack and keeps the toolchain out of the run image", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for CPython")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Gyp")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Start")))
+
+			for _, buildpack := range image.Buildpacks {
+				if buildpack.Key == "paketo-buildpacks/cpython" || buildpack.Key == "paketo-buildpacks/node-gyp" {
+					Expect(buildpack.Layers).To(BeEmpty())
+				}
+			}
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container, "5s").Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("hello from a native addon")).OnPort(8080))
+		})
+	})
 }
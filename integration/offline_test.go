@@ -0,0 +1,203 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+// The offline buildpackage vendors every component buildpack, which takes
+// long enough that it is only packaged once the offline suite runs.
+var (
+	offlineNodeBuildpack     string
+	offlineNodeBuildpackOnce sync.Once
+	offlineNodeBuildpackErr  error
+)
+
+func packageOfflineNodeBuildpack() error {
+	offlineNodeBuildpackOnce.Do(func() {
+		offlineNodeBuildpack, offlineNodeBuildpackErr = filepath.Abs("../build/buildpackage-offline.cnb")
+		if offlineNodeBuildpackErr != nil {
+			return
+		}
+
+		output, err := exec.Command("bash", "-c", fmt.Sprintf("../scripts/package-offline.sh --version 1.2.3 --output %s", offlineNodeBuildpack)).CombinedOutput()
+		if err != nil {
+			offlineNodeBuildpackErr = fmt.Errorf("failed to package the offline buildpack: %w: %s", err, output)
+		}
+	})
+
+	return offlineNodeBuildpackErr
+}
+
+func testOffline(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		// The UBI Node.js extension installs Node.js from the network, so
+		// offline builds are only exercised on the other builders.
+		if settings.Extensions.UbiNodejsExtension.Online != "" {
+			t.Skip("offline builds are not supported with the UBI Node.js extension")
+		}
+
+		Expect(packageOfflineNodeBuildpack()).To(Succeed())
+
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building with the offline buildpackage and networking disabled", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the app does not use a package manager", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "no_package_manager"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("builds a working OCI image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(offlineNodeBuildpack).
+					WithPullPolicy("never").
+					WithNetwork("none").
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
+			})
+		})
+
+		context("when the app uses npm with vendored node_modules", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "npm"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(os.CopyFS(filepath.Join(source, "node_modules"), os.DirFS(filepath.Join("testdata", "vendored", "node_modules")))).To(Succeed())
+			})
+
+			it("builds a working OCI image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(offlineNodeBuildpack).
+					WithPullPolicy("never").
+					WithNetwork("none").
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+			})
+		})
+
+		context("when the app uses yarn with an offline mirror", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "yarn"))
+				Expect(err).NotTo(HaveOccurred())
+
+				leftpad, err := packRegistryPackage(filepath.Join("testdata", "vendored", "node_modules", "leftpad"))
+				Expect(err).NotTo(HaveOccurred())
+
+				mirror := filepath.Join(source, "npm-packages-offline-cache")
+				Expect(os.MkdirAll(mirror, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(mirror, "leftpad-0.0.1.tgz"), leftpad.tarball, 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(source, ".yarnrc"), []byte("yarn-offline-mirror \"./npm-packages-offline-cache\"\n"), 0644)).To(Succeed())
+
+				lockfile := fmt.Sprintf(`# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+leftpad@~0.0.1:
+  version "0.0.1"
+  resolved "https://registry.yarnpkg.com/leftpad/-/leftpad-0.0.1.tgz"
+  integrity %s
+`, leftpad.integrity)
+				Expect(os.WriteFile(filepath.Join(source, "yarn.lock"), []byte(lockfile), 0644)).To(Succeed())
+			})
+
+			it("builds a working OCI image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithBuildpacks(offlineNodeBuildpack).
+					WithPullPolicy("never").
+					WithNetwork("none").
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("Hello, World!")).OnPort(8080))
+			})
+		})
+	})
+}
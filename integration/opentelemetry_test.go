@@ -0,0 +1,192 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testOpenTelemetry(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building an app with OpenTelemetry auto-instrumentation", func() {
+		const serverSpanKind = 2
+
+		var (
+			image        occam.Image
+			container    occam.Container
+			envContainer occam.Container
+			collector    *otlpCollector
+
+			name     string
+			source   string
+			endpoint string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "opentelemetry"))
+			Expect(err).NotTo(HaveOccurred())
+
+			collector, err = newOTLPCollector()
+			Expect(err).NotTo(HaveOccurred())
+
+			endpoint = collector.Endpoint()
+		})
+
+		it.After(func() {
+			collector.Close()
+
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Container.Remove.Execute(envContainer.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when enabled with BP_OPENTELEMETRY_ENABLED", func() {
+			it("exports a span for each request to the collector", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"BP_OPENTELEMETRY_ENABLED": "true"}).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for OpenTelemetry")))
+
+				env := map[string]string{
+					"PORT":                        "8080",
+					"OTEL_SERVICE_NAME":           "opentelemetry-fixture",
+					"OTEL_EXPORTER_OTLP_ENDPOINT": endpoint,
+					"OTEL_EXPORTER_OTLP_PROTOCOL": "http/json",
+				}
+
+				container, err = docker.Container.Run.
+					WithEnv(env).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello from an instrumented app")).OnPort(8080))
+
+				Eventually(collector.Spans, "30s").Should(ContainElement(otlpSpan{
+					ServiceName: "opentelemetry-fixture",
+					Name:        "GET",
+					Kind:        serverSpanKind,
+				}))
+
+				envContainer, err = docker.Container.Run.
+					WithEnv(env).
+					WithEntrypoint("launcher").
+					WithCommand(`node -e 'console.log("NODE_OPTIONS=" + process.env.NODE_OPTIONS)'`).
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(envContainer.ID)
+					return clogs.String()
+				}).Should(MatchRegexp(`NODE_OPTIONS=.*--require \S*@opentelemetry/auto-instrumentations-node/register`))
+			})
+		})
+
+		context("when enabled with an otel service binding", func() {
+			var bindingDir string
+
+			it.Before(func() {
+				var err error
+				bindingDir, err = os.MkdirTemp("", "bindings")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.Chmod(bindingDir, os.ModePerm)).To(Succeed())
+
+				Expect(os.WriteFile(filepath.Join(bindingDir, "type"), []byte("otel"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, "endpoint"), []byte(endpoint), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, "protocol"), []byte("http/json"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, "service-name"), []byte("opentelemetry-binding-fixture"), 0644)).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(bindingDir)).To(Succeed())
+			})
+
+			it("exports a span for each request to the collector from the binding", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+					WithVolumes(fmt.Sprintf("%s:/bindings/otel", bindingDir)).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for OpenTelemetry")))
+
+				env := map[string]string{
+					"PORT":                 "8080",
+					"SERVICE_BINDING_ROOT": "/bindings",
+				}
+
+				container, err = docker.Container.Run.
+					WithEnv(env).
+					WithVolumes(fmt.Sprintf("%s:/bindings/otel", bindingDir)).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello from an instrumented app")).OnPort(8080))
+
+				Eventually(collector.Spans, "30s").Should(ContainElement(otlpSpan{
+					ServiceName: "opentelemetry-binding-fixture",
+					Name:        "GET",
+					Kind:        serverSpanKind,
+				}))
+
+				envContainer, err = docker.Container.Run.
+					WithEnv(env).
+					WithVolumes(fmt.Sprintf("%s:/bindings/otel", bindingDir)).
+					WithEntrypoint("launcher").
+					WithCommand(`node -e 'console.log("NODE_OPTIONS=" + process.env.NODE_OPTIONS)'`).
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(envContainer.ID)
+					return clogs.String()
+				}).Should(MatchRegexp(`NODE_OPTIONS=.*--require \S*@opentelemetry/auto-instrumentations-node/register`))
+			})
+		})
+	})
+}
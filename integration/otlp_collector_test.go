@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// otlpCollector is a stand-in for an OpenTelemetry collector. It accepts
+// OTLP/HTTP trace exports encoded as JSON and records every span it receives.
+// It listens on the address of the host on the default docker bridge network
+// so that it can be reached from containers.
+type otlpCollector struct {
+	server *httptest.Server
+
+	m     sync.Mutex
+	spans []otlpSpan
+}
+
+type otlpSpan struct {
+	ServiceName string
+	Name        string
+	Kind        int
+}
+
+func newOTLPCollector() (*otlpCollector, error) {
+	listener, err := listenOnDockerBridge()
+	if err != nil {
+		return nil, err
+	}
+
+	collector := &otlpCollector{}
+	collector.server = httptest.NewUnstartedServer(http.HandlerFunc(collector.serveHTTP))
+	collector.server.Listener = listener
+	collector.server.Start()
+
+	return collector, nil
+}
+
+// Endpoint returns the OTLP endpoint of the collector as seen from inside a
+// container attached to the default docker bridge network.
+func (c *otlpCollector) Endpoint() string {
+	return c.server.URL
+}
+
+// Spans returns the spans that have been received so far.
+func (c *otlpCollector) Spans() []otlpSpan {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return append([]otlpSpan{}, c.spans...)
+}
+
+func (c *otlpCollector) Close() {
+	c.server.Close()
+}
+
+func (c *otlpCollector) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || req.URL.Path != "/v1/traces" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var request struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value struct {
+						StringValue string `json:"stringValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					Name string `json:"name"`
+					Kind int    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.m.Lock()
+	for _, resourceSpans := range request.ResourceSpans {
+		var serviceName string
+		for _, attribute := range resourceSpans.Resource.Attributes {
+			if attribute.Key == "service.name" {
+				serviceName = attribute.Value.StringValue
+			}
+		}
+
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				c.spans = append(c.spans, otlpSpan{
+					ServiceName: serviceName,
+					Name:        span.Name,
+					Kind:        span.Kind,
+				})
+			}
+		}
+	}
+	c.m.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("{}"))
+}
@@ -0,0 +1,210 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testPackageManager(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when the package.json declares a packageManager", func() {
+		var (
+			image            occam.Image
+			container        occam.Container
+			versionContainer occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "package_manager"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Container.Remove.Execute(versionContainer.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the declared manager is npm", func() {
+			it("provisions the declared npm version through corepack", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "npm"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Corepack")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
+
+				versionContainer, err = docker.Container.Run.
+					WithEntrypoint("launcher").
+					WithCommand("npm --version").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(versionContainer.ID)
+					return clogs.String()
+				}).Should(ContainSubstring("10.5.0"))
+			})
+		})
+
+		context("when the declared manager is yarn", func() {
+			it("provisions the declared yarn version through corepack", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "yarn"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Corepack")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
+
+				versionContainer, err = docker.Container.Run.
+					WithEntrypoint("launcher").
+					WithCommand("yarn --version").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(versionContainer.ID)
+					return clogs.String()
+				}).Should(ContainSubstring("4.1.0"))
+			})
+		})
+
+		context("when the declared manager is pnpm", func() {
+			it("provisions the declared pnpm version through corepack", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "pnpm"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Corepack")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Pnpm Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Pnpm Start")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
+
+				versionContainer, err = docker.Container.Run.
+					WithEntrypoint("launcher").
+					WithCommand("pnpm --version").
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() string {
+					clogs, _ := docker.Container.Logs.Execute(versionContainer.ID)
+					return clogs.String()
+				}).Should(ContainSubstring("9.0.0"))
+			})
+		})
+	})
+
+	context("when the lockfile contradicts the declared packageManager", func() {
+		var (
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "package_manager"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("fails the build with a message naming both package managers", func() {
+			_, logs, err := pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				Execute(name, filepath.Join(source, "mismatch"))
+			Expect(err).To(HaveOccurred())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Corepack")))
+			Expect(logs).To(ContainLines(
+				ContainSubstring(`package.json declares "packageManager": "pnpm@9.0.0" but the app contains a yarn.lock`),
+			))
+		})
+	})
+}
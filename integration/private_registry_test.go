@@ -0,0 +1,209 @@
+package integration_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testPrivateRegistry(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when installing dependencies from a private registry", func() {
+		const token = "some-secret-registry-token"
+
+		var (
+			image     occam.Image
+			container occam.Container
+			registry  *npmRegistry
+
+			name       string
+			source     string
+			bindingDir string
+		)
+
+		// imageContains reports whether any file in the saved image, including
+		// the files inside each of its layers, contains the given value.
+		imageContains := func(imageID, value string) bool {
+			archive := filepath.Join(t.TempDir(), "image.tar")
+			output, err := exec.Command("docker", "save", "--output", archive, imageID).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(output))
+
+			var search func(r io.Reader) bool
+			search = func(r io.Reader) bool {
+				tr := tar.NewReader(r)
+				for {
+					header, err := tr.Next()
+					if errors.Is(err, io.EOF) {
+						return false
+					}
+					Expect(err).NotTo(HaveOccurred())
+
+					if header.Typeflag != tar.TypeReg {
+						continue
+					}
+
+					content, err := io.ReadAll(tr)
+					Expect(err).NotTo(HaveOccurred())
+
+					if bytes.Contains(content, []byte(value)) {
+						return true
+					}
+
+					if gr, err := gzip.NewReader(bytes.NewReader(content)); err == nil {
+						content, err = io.ReadAll(gr)
+						if err == nil && bytes.Contains(content, []byte(value)) {
+							return true
+						}
+					}
+
+					if _, err := tar.NewReader(bytes.NewReader(content)).Next(); err == nil && search(bytes.NewReader(content)) {
+						return true
+					}
+				}
+			}
+
+			file, err := os.Open(archive)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				Expect(file.Close()).To(Succeed())
+			}()
+
+			return search(file)
+		}
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "private_registry"))
+			Expect(err).NotTo(HaveOccurred())
+
+			registry, err = newNPMRegistry(filepath.Join(source, "packages"), token)
+			Expect(err).NotTo(HaveOccurred())
+
+			bindingDir, err = os.MkdirTemp("", "bindings")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chmod(bindingDir, os.ModePerm)).To(Succeed())
+		})
+
+		it.After(func() {
+			registry.Close()
+
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(bindingDir)).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the app uses npm with an npmrc binding", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(bindingDir, "type"), []byte("npmrc"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, ".npmrc"), []byte(fmt.Sprintf("registry=%s/\n//%s/:_authToken=%s\n", registry.URL, registry.Host(), token)), 0644)).To(Succeed())
+			})
+
+			it("installs the private dependency and does not leave the token in the image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+					WithVolumes(fmt.Sprintf("%s:/bindings/npmrc", bindingDir)).
+					Execute(name, filepath.Join(source, "npm"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+
+				Expect(imageContains(image.ID, token)).To(BeFalse())
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring("Hello from the private registry!")).OnPort(8080))
+			})
+		})
+
+		context("when the app uses yarn with a yarnrc binding", func() {
+			it.Before(func() {
+				lockfile := fmt.Sprintf(`# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@internal/secret-greeting@^1.0.0":
+  version "1.0.0"
+  resolved "%s"
+  integrity %s
+`, registry.TarballURL("@internal/secret-greeting"), registry.Integrity("@internal/secret-greeting"))
+				Expect(os.WriteFile(filepath.Join(source, "yarn", "yarn.lock"), []byte(lockfile), 0644)).To(Succeed())
+
+				Expect(os.WriteFile(filepath.Join(bindingDir, "type"), []byte("yarnrc"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(bindingDir, ".yarnrc"), []byte(fmt.Sprintf("registry %q\n\"//%s/:_authToken\" %q\n", registry.URL+"/", registry.Host(), token)), 0644)).To(Succeed())
+			})
+
+			it("installs the private dependency and does not leave the token in the image", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"SERVICE_BINDING_ROOT": "/bindings"}).
+					WithVolumes(fmt.Sprintf("%s:/bindings/yarnrc", bindingDir)).
+					Execute(name, filepath.Join(source, "yarn"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+
+				Expect(imageContains(image.ID, token)).To(BeFalse())
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring("Hello from the private registry!")).OnPort(8080))
+			})
+		})
+	})
+}
@@ -0,0 +1,138 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testPuppeteer(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building an app that renders PDFs with Puppeteer", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		// renderPDF requests a PDF from the app and returns the response body.
+		renderPDF := func() []byte {
+			client := http.Client{Timeout: time.Minute}
+
+			response, err := client.Get(fmt.Sprintf("http://localhost:%s/pdf", container.HostPort("8080")))
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				Expect(response.Body.Close()).To(Succeed())
+			}()
+
+			content, err := io.ReadAll(response.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.StatusCode).To(Equal(http.StatusOK), string(content))
+			Expect(response.Header.Get("Content-Type")).To(Equal("application/pdf"))
+
+			return content
+		}
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "puppeteer"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		context("when the app uses npm", func() {
+			it("provides Chromium and renders a page to PDF inside the container", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "npm"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Chromium")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(MatchRegexp(`Chromium executable: /layers/\S+`)).OnPort(8080))
+
+				Expect(renderPDF()).To(HavePrefix("%PDF-"))
+			})
+		})
+
+		context("when the app uses yarn", func() {
+			it("provides Chromium and renders a page to PDF inside the container", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					Execute(name, filepath.Join(source, "yarn"))
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Chromium")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(MatchRegexp(`Chromium executable: /layers/\S+`)).OnPort(8080))
+
+				Expect(renderPDF()).To(HavePrefix("%PDF-"))
+			})
+		})
+	})
+}
@@ -0,0 +1,211 @@
+package integration_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// npmRegistry is a stand-in for a private npm registry. It serves every
+// package found in a directory of unpacked packages as a single-version
+// packument and tarball, and rejects requests that do not carry the expected
+// auth token. It listens on the docker bridge gateway so that builds on the
+// default bridge network can reach it.
+type npmRegistry struct {
+	URL string
+
+	server   *httptest.Server
+	token    string
+	packages map[string]registryPackage
+}
+
+type registryPackage struct {
+	manifest  map[string]interface{}
+	tarball   []byte
+	integrity string
+	shasum    string
+}
+
+func newNPMRegistry(packagesDir, token string) (*npmRegistry, error) {
+	entries, err := os.ReadDir(packagesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &npmRegistry{
+		token:    token,
+		packages: map[string]registryPackage{},
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		pkg, err := packRegistryPackage(filepath.Join(packagesDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		registry.packages[pkg.manifest["name"].(string)] = pkg
+	}
+
+	listener, err := listenOnDockerBridge()
+	if err != nil {
+		return nil, err
+	}
+
+	registry.server = httptest.NewUnstartedServer(http.HandlerFunc(registry.serveHTTP))
+	registry.server.Listener = listener
+	registry.server.Start()
+	registry.URL = registry.server.URL
+
+	return registry, nil
+}
+
+// Host returns the host and port of the registry, as used in the
+// "//host/:_authToken" keys of npmrc and yarnrc files.
+func (r *npmRegistry) Host() string {
+	return strings.TrimPrefix(r.URL, "http://")
+}
+
+// TarballURL returns the URL that the tarball of the named package is served
+// from.
+func (r *npmRegistry) TarballURL(name string) string {
+	pkg := r.packages[name]
+	return fmt.Sprintf("%s/%s/-/%s-%s.tgz", r.URL, name, path.Base(name), pkg.manifest["version"])
+}
+
+// Integrity returns the sha512 subresource integrity of the tarball of the
+// named package.
+func (r *npmRegistry) Integrity(name string) string {
+	return r.packages[name].integrity
+}
+
+func (r *npmRegistry) Close() {
+	r.server.Close()
+}
+
+func (r *npmRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", r.token) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name, tarball, isTarball := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/-/")
+
+	pkg, ok := r.packages[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if isTarball {
+		if tarball != path.Base(r.TarballURL(name)) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(pkg.tarball)
+		return
+	}
+
+	version := pkg.manifest["version"].(string)
+
+	manifest := map[string]interface{}{}
+	for key, value := range pkg.manifest {
+		manifest[key] = value
+	}
+	manifest["dist"] = map[string]string{
+		"tarball":   r.TarballURL(name),
+		"integrity": pkg.integrity,
+		"shasum":    pkg.shasum,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":      name,
+		"dist-tags": map[string]string{"latest": version},
+		"versions":  map[string]interface{}{version: manifest},
+	})
+}
+
+func packRegistryPackage(dir string) (registryPackage, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return registryPackage{}, err
+	}
+
+	var manifest map[string]interface{}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return registryPackage{}, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "package.json"), err)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buffer)
+	tw := tar.NewWriter(gw)
+
+	err = filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		err = tw.WriteHeader(&tar.Header{
+			Name: filepath.ToSlash(filepath.Join("package", rel)),
+			Mode: 0644,
+			Size: int64(len(content)),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(content)
+		return err
+	})
+	if err != nil {
+		return registryPackage{}, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return registryPackage{}, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return registryPackage{}, err
+	}
+
+	sha512sum := sha512.Sum512(buffer.Bytes())
+	sha1sum := sha1.Sum(buffer.Bytes())
+
+	return registryPackage{
+		manifest:  manifest,
+		tarball:   buffer.Bytes(),
+		integrity: fmt.Sprintf("sha512-%s", base64.StdEncoding.EncodeToString(sha512sum[:])),
+		shasum:    hex.EncodeToString(sha1sum[:]),
+	}, nil
+}
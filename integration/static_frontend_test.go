@@ -0,0 +1,142 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testStaticFrontend(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a single page app that is served by a web server", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		build := func() fmt.Stringer {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+				WithBuildpacks(nodeBuildpack).
+				WithPullPolicy(pullPolicy).
+				WithEnv(map[string]string{
+					"BP_NODE_RUN_SCRIPTS": "build",
+					"BP_WEB_SERVER":       "nginx",
+					"BP_WEB_SERVER_ROOT":  "build",
+				}).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Run Script")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Nginx Server")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Node Start")))
+
+			// node is only needed to run the build script, so it must not be on
+			// the PATH of the launch environment.
+			pathContainer, err := docker.Container.Run.
+				WithEntrypoint("launcher").
+				WithCommand("echo node=$(command -v node || echo missing)").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				Expect(docker.Container.Remove.Execute(pathContainer.ID)).To(Succeed())
+			}()
+
+			Eventually(func() string {
+				clogs, _ := docker.Container.Logs.Execute(pathContainer.ID)
+				return clogs.String()
+			}).Should(ContainSubstring("node=missing"))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The build script replaces the {{BUILT_WITH}} placeholder of
+			// src/index.html with the version of node, so the page is only served
+			// like this from the build output.
+			Eventually(container).Should(BeAvailable())
+			Eventually(container).Should(Serve(MatchRegexp(`Hello from a static frontend built with node v\d+\.\d+\.\d+`)).OnPort(8080))
+
+			return logs
+		}
+
+		context("when the app uses npm", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "static_frontend", "npm"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("builds the app with node and serves the build output without node at launch", func() {
+				logs := build()
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+			})
+		})
+
+		context("when the app uses yarn", func() {
+			it.Before(func() {
+				var err error
+				source, err = occam.Source(filepath.Join("testdata", "static_frontend", "yarn"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("builds the app with node and serves the build output without node at launch", func() {
+				logs := build()
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+				Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+			})
+		})
+	})
+}
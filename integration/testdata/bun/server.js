@@ -0,0 +1,11 @@
+const leftpad = require('leftpad')
+const port = process.env.PORT || 8080
+
+const server = Bun.serve({
+  port: port,
+  fetch(request) {
+    return new Response(`hello world from bun ${Bun.version}`)
+  },
+})
+
+console.log(`server is listening on ${server.port}`)
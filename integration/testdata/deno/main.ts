@@ -0,0 +1,7 @@
+import leftpad from "leftpad";
+
+const port = Number(Deno.env.get("PORT") ?? "8080");
+
+Deno.serve({ port }, (_request: Request) => {
+  return new Response(`hello world from deno ${leftpad(Deno.version.deno, 0)}`);
+});
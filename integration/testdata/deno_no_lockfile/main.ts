@@ -0,0 +1,5 @@
+const port = Number(Deno.env.get("PORT") ?? "8080");
+
+Deno.serve({ port }, (_request: Request) => {
+  return new Response(`hello world from deno ${Deno.version.deno}`);
+});
@@ -0,0 +1 @@
+module.exports = () => "Hello from a git dependency!"
@@ -0,0 +1,21 @@
+const http = require('http')
+const port = process.env.PORT || 8080
+
+const requestHandler = (request, response) => {
+  if (request.url !== '/healthz') {
+    response.statusCode = 404
+    return response.end("not found")
+  }
+
+  response.end("ok")
+}
+
+const server = http.createServer(requestHandler)
+
+server.listen(port, (err) => {
+  if (err) {
+    return console.log('something bad happened', err)
+  }
+
+  console.log(`server is listening on ${port}`)
+})
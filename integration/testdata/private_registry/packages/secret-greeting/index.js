@@ -0,0 +1 @@
+module.exports = () => "Hello from the private registry!"
@@ -0,0 +1,17 @@
+const http = require('http')
+const greeting = require('@internal/secret-greeting')
+const port = process.env.PORT || 8080
+
+const requestHandler = (request, response) => {
+  response.end(greeting())
+}
+
+const server = http.createServer(requestHandler)
+
+server.listen(port, (err) => {
+  if (err) {
+    return console.log('something bad happened', err)
+  }
+
+  console.log(`server is listening on ${port}`)
+})
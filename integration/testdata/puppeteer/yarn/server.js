@@ -0,0 +1,45 @@
+const http = require('http')
+const puppeteer = require('puppeteer')
+
+const port = process.env.PORT || 8080
+
+const renderPDF = async () => {
+  const browser = await puppeteer.launch({
+    headless: true,
+    args: ['--no-sandbox', '--disable-dev-shm-usage'],
+  })
+
+  try {
+    const page = await browser.newPage()
+    await page.setContent('<h1>Hello from Chromium!</h1>')
+    return await page.pdf({ format: 'A4' })
+  } finally {
+    await browser.close()
+  }
+}
+
+const requestHandler = async (request, response) => {
+  if (request.url !== '/pdf') {
+    response.end(`Chromium executable: ${process.env.PUPPETEER_EXECUTABLE_PATH}`)
+    return
+  }
+
+  try {
+    const pdf = await renderPDF()
+    response.writeHead(200, { 'Content-Type': 'application/pdf' })
+    response.end(pdf)
+  } catch (err) {
+    response.writeHead(500)
+    response.end(err.stack)
+  }
+}
+
+const server = http.createServer(requestHandler)
+
+server.listen(port, (err) => {
+  if (err) {
+    return console.log('something bad happened', err)
+  }
+
+  console.log(`server is listening on ${port}`)
+})
@@ -0,0 +1,10 @@
+const fs = require('fs')
+const path = require('path')
+
+const output = path.join(__dirname, 'build')
+fs.mkdirSync(output, { recursive: true })
+
+const template = fs.readFileSync(path.join(__dirname, 'src', 'index.html'), 'utf8')
+fs.writeFileSync(path.join(output, 'index.html'), template.replace('{{BUILT_WITH}}', `node ${process.version}`))
+
+console.log(`built ${path.join(output, 'index.html')}`)
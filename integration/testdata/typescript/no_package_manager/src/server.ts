@@ -0,0 +1,22 @@
+declare const require: { (id: string): any, resolve(id: string): string }
+declare const process: { env: { [key: string]: string | undefined } }
+
+const http = require('http')
+const port: number = Number(process.env.PORT || 8080)
+
+const hasTypeScript = (): boolean => {
+  try {
+    require.resolve('typescript')
+    return true
+  } catch {
+    return false
+  }
+}
+
+const server = http.createServer((request: any, response: any) => {
+  response.end(`Hello from TypeScript! typescript installed: ${hasTypeScript()}`)
+})
+
+server.listen(port, () => {
+  console.log(`server is listening on ${port}`)
+})
@@ -0,0 +1 @@
+module.exports = () => "Hello from a Plug'n'Play workspace!"
@@ -0,0 +1,18 @@
+const http = require('http')
+const greeting = require('greeting')
+const ms = require('ms')
+const port = process.env.PORT || 8080
+
+const requestHandler = (request, response) => {
+  response.end(`${greeting()} ${ms(60000)} pnp=${process.versions.pnp !== undefined}`)
+}
+
+const server = http.createServer(requestHandler)
+
+server.listen(port, (err) => {
+  if (err) {
+    return console.log('something bad happened', err)
+  }
+
+  console.log(`server is listening on ${port}`)
+})
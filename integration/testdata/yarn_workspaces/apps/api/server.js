@@ -0,0 +1,17 @@
+const http = require('http')
+const leftpad = require('leftpad')
+const port = process.env.PORT || 8080
+
+const requestHandler = (request, response) => {
+  response.end("Hello from the api workspace!")
+}
+
+const server = http.createServer(requestHandler)
+
+server.listen(port, (err) => {
+  if (err) {
+    return console.log('something bad happened', err)
+  }
+
+  console.log(`api server is listening on ${port}`)
+})
@@ -0,0 +1,161 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testWorkspaces(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+
+		pullPolicy = "never"
+	)
+
+	if settings.Extensions.UbiNodejsExtension.Online != "" {
+		pullPolicy = "always"
+	}
+
+	it.Before(func() {
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building each app of an npm workspaces monorepo", func() {
+		var (
+			images     map[string]occam.Image
+			containers map[string]occam.Container
+
+			names  map[string]string
+			source string
+		)
+
+		it.Before(func() {
+			images = map[string]occam.Image{}
+			containers = map[string]occam.Container{}
+			names = map[string]string{}
+
+			for _, app := range []string{"api", "web"} {
+				name, err := occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+				names[app] = name
+			}
+
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "npm_workspaces"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			for app, name := range names {
+				Expect(docker.Container.Remove.Execute(containers[app].ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(images[app].ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			}
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("installs from the root lockfile and launches only the selected app", func() {
+			for _, app := range []string{"api", "web"} {
+				image, logs, err := pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"BP_NODE_PROJECT_PATH": filepath.Join("apps", app)}).
+					Execute(names[app], source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+				images[app] = image
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for NPM Start")))
+
+				container, err := docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers[app] = container
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring(fmt.Sprintf("Hello from the %s workspace!", app))).OnPort(8080))
+			}
+		})
+	})
+
+	context("when building each app of a yarn workspaces monorepo", func() {
+		var (
+			images     map[string]occam.Image
+			containers map[string]occam.Container
+
+			names  map[string]string
+			source string
+		)
+
+		it.Before(func() {
+			images = map[string]occam.Image{}
+			containers = map[string]occam.Container{}
+			names = map[string]string{}
+
+			for _, app := range []string{"api", "web"} {
+				name, err := occam.RandomName()
+				Expect(err).NotTo(HaveOccurred())
+				names[app] = name
+			}
+
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "yarn_workspaces"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			for app, name := range names {
+				Expect(docker.Container.Remove.Execute(containers[app].ID)).To(Succeed())
+				Expect(docker.Image.Remove.Execute(images[app].ID)).To(Succeed())
+				Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			}
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("installs from the root lockfile and launches only the selected app", func() {
+			for _, app := range []string{"api", "web"} {
+				image, logs, err := pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithEnv(map[string]string{"BP_NODE_PROJECT_PATH": filepath.Join("apps", app)}).
+					Execute(names[app], source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+				images[app] = image
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+
+				container, err := docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+				containers[app] = container
+
+				Eventually(container).Should(BeAvailable())
+				Eventually(container).Should(Serve(ContainSubstring(fmt.Sprintf("Hello from the %s workspace!", app))).OnPort(8080))
+			}
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/occam"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/occam/matchers"
+)
+
+func testYarnBerry(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect     = NewWithT(t).Expect
+		Eventually = NewWithT(t).Eventually
+
+		pack   occam.Pack
+		docker occam.Docker
+	)
+
+	it.Before(func() {
+		// The zero-install build runs with networking disabled, which needs
+		// the offline buildpackage, and the UBI Node.js extension installs
+		// Node.js from the network.
+		if settings.Extensions.UbiNodejsExtension.Online != "" {
+			t.Skip("offline builds are not supported with the UBI Node.js extension")
+		}
+
+		Expect(packageOfflineNodeBuildpack()).To(Succeed())
+
+		pack = occam.NewPack()
+		docker = occam.NewDocker()
+	})
+
+	context("when building a node app that uses yarn berry with plug'n'play and zero-installs with networking disabled", func() {
+		var (
+			image     occam.Image
+			container occam.Container
+
+			name   string
+			source string
+		)
+
+		it.Before(func() {
+			var err error
+			name, err = occam.RandomName()
+			Expect(err).NotTo(HaveOccurred())
+			source, err = occam.Source(filepath.Join("testdata", "yarn_berry"))
+			Expect(err).NotTo(HaveOccurred())
+
+			// The Yarn release, the cache and the PnP loader are committed
+			// along with the fixture, like in a zero-install project, and are
+			// regenerated with scripts/yarn-berry-fixture.sh.
+			for _, path := range []string{
+				filepath.Join(".yarn", "releases", "yarn-4.5.3.cjs"),
+				".pnp.cjs",
+				"yarn.lock",
+			} {
+				Expect(filepath.Join(source, path)).To(BeARegularFile(), "%s is missing from the fixture, run scripts/yarn-berry-fixture.sh and commit the result", path)
+			}
+			Expect(filepath.Join(source, "node_modules")).NotTo(BeAnExistingFile())
+
+			cache, err := filepath.Glob(filepath.Join(source, ".yarn", "cache", "ms-npm-2.1.3-*.zip"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache).To(HaveLen(1))
+		})
+
+		it.After(func() {
+			Expect(docker.Container.Remove.Execute(container.ID)).To(Succeed())
+			Expect(docker.Image.Remove.Execute(image.ID)).To(Succeed())
+			Expect(docker.Volume.Remove.Execute(occam.CacheVolumeNames(name))).To(Succeed())
+			Expect(os.RemoveAll(source)).To(Succeed())
+		})
+
+		it("should build a working OCI image that launches the app with the PnP loader", func() {
+			var err error
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithBuildpacks(offlineNodeBuildpack).
+				WithPullPolicy("never").
+				WithNetwork("none").
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Engine")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+			Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Start")))
+			Expect(logs).NotTo(ContainLines(ContainSubstring("Buildpack for NPM Install")))
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "8080"}).
+				WithPublish("8080").
+				WithPublishAll().
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(BeAvailable())
+			Eventually(container).Should(Serve(ContainSubstring("Hello from a Plug'n'Play workspace! 1m pnp=true")).OnPort(8080))
+		})
+	})
+}
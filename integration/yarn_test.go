@@ -212,13 +212,22 @@ func testYarn(t *testing.T, context spec.G, it spec.S) {
 		})
 
 		context("when using optional utility buildpacks", func() {
-			var procfileContainer occam.Container
+			var (
+				procfileContainer occam.Container
+				bindings          *apmBindings
+			)
+
 			it.Before(func() {
 				Expect(os.WriteFile(filepath.Join(source, "Procfile"), []byte("procfile: echo Procfile command"), 0644)).To(Succeed())
+
+				var err error
+				bindings, err = newAPMBindings()
+				Expect(err).NotTo(HaveOccurred())
 			})
 
 			it.After(func() {
 				Expect(docker.Container.Remove.Execute(procfileContainer.ID)).To(Succeed())
+				Expect(bindings.Close()).To(Succeed())
 			})
 
 			it("should build a working OCI image and run the app with the start command from the Procfile and other utility buildpacks", func() {
@@ -234,7 +243,9 @@ func testYarn(t *testing.T, context spec.G, it spec.S) {
 						"BP_NODE_RUN_SCRIPTS":    "some-script",
 						"BP_LIVE_RELOAD_ENABLED": "true",
 						"BP_DATADOG_ENABLED":     "true",
+						"SERVICE_BINDING_ROOT":   "/bindings",
 					}).
+					WithVolumes(bindings.Volumes()...).
 					Execute(name, source)
 				Expect(err).NotTo(HaveOccurred())
 
@@ -247,6 +258,9 @@ func testYarn(t *testing.T, context spec.G, it spec.S) {
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Procfile")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Environment Variables")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Image Labels")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Datadog")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for New Relic")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Dynatrace")))
 				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Run Script")))
 
 				environmentVariables, err := image.BuildpackForKey("paketo-buildpacks/environment-variables")
@@ -274,6 +288,57 @@ func testYarn(t *testing.T, context spec.G, it spec.S) {
 					clogs, _ := docker.Container.Logs.Execute(procfileContainer.ID)
 					return clogs.String()
 				}).Should(ContainSubstring("Procfile command"))
+
+				bindings.ExpectAgentsLoaded(t, docker, image.ID)
+			})
+		})
+
+		context("when generating a software bill of materials for node modules", func() {
+			var sbomDir string
+
+			it.Before(func() {
+				var err error
+				sbomDir, err = os.MkdirTemp("", "sbom")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(os.Chmod(sbomDir, os.ModePerm)).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(sbomDir)).To(Succeed())
+			})
+
+			it("writes CycloneDX and SPDX SBOMs that list the installed modules", func() {
+				var err error
+				var logs fmt.Stringer
+				image, logs, err = pack.WithNoColor().Build.
+					WithExtensions(settings.Extensions.UbiNodejsExtension.Online).
+					WithBuildpacks(nodeBuildpack).
+					WithPullPolicy(pullPolicy).
+					WithSBOMOutputDir(sbomDir).
+					Execute(name, source)
+				Expect(err).NotTo(HaveOccurred(), logs.String())
+
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Yarn Install")))
+				Expect(logs).To(ContainLines(ContainSubstring("Buildpack for Node Module Bill of Materials")))
+
+				for _, format := range []string{"sbom.cdx.json", "sbom.spdx.json"} {
+					matches, err := filepath.Glob(filepath.Join(sbomDir, "sbom", "launch", "paketo-buildpacks_node-module-bom", "*", format))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(matches).NotTo(BeEmpty(), fmt.Sprintf("no %s found in %s", format, sbomDir))
+
+					contents, err := os.ReadFile(matches[0])
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(ContainSubstring(`"leftpad"`))
+				}
+
+				container, err = docker.Container.Run.
+					WithEnv(map[string]string{"PORT": "8080"}).
+					WithPublish("8080").
+					WithPublishAll().
+					Execute(image.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(container).Should(BeAvailable())
 			})
 		})
 
@@ -0,0 +1,190 @@
+// Package composite reads the buildpack.toml and package.toml of the composite
+// buildpack, and holds the version helpers that the commands in cmd share.
+package composite
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Buildpack is the part of buildpack.toml that lists the order groups.
+type Buildpack struct {
+	Order []Order `toml:"order"`
+}
+
+// Order is a single [[order]] table of buildpack.toml.
+type Order struct {
+	Group []Member `toml:"group"`
+}
+
+// Member is a component buildpack in an order group.
+type Member struct {
+	ID       string `toml:"id"`
+	Version  string `toml:"version"`
+	Optional bool   `toml:"optional"`
+}
+
+// Package is the part of package.toml that lists the component buildpacks.
+type Package struct {
+	Dependencies []struct {
+		URI string `toml:"uri"`
+	} `toml:"dependencies"`
+}
+
+// DecodeBuildpack parses the buildpack.toml at path.
+func DecodeBuildpack(path string) (Buildpack, error) {
+	var buildpack Buildpack
+	_, err := toml.DecodeFile(path, &buildpack)
+	if err != nil {
+		return Buildpack{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return buildpack, nil
+}
+
+// DecodePackage parses the package.toml at path.
+func DecodePackage(path string) (Package, error) {
+	var pkg Package
+	_, err := toml.DecodeFile(path, &pkg)
+	if err != nil {
+		return Package{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return pkg, nil
+}
+
+// Dependency is a component buildpack referenced from package.toml.
+type Dependency struct {
+	// ID is the id of the buildpack in the image, such as
+	// paketo-buildpacks/node-engine.
+	ID string
+
+	// Registry and Repository locate the image, such as docker.io and
+	// paketobuildpacks/node-engine.
+	Registry   string
+	Repository string
+
+	Version string
+	URI     string
+}
+
+// ParseDependencyURI maps a package.toml dependency such as
+// "docker://docker.io/paketobuildpacks/node-engine:1.2.3" onto the image it
+// references and the id and version of the buildpack it contains, in this case
+// "paketo-buildpacks/node-engine" and "1.2.3".
+func ParseDependencyURI(uri string) (Dependency, error) {
+	reference, ok := strings.CutPrefix(uri, "docker://")
+	if !ok {
+		return Dependency{}, fmt.Errorf("dependency %q is not a docker:// image reference", uri)
+	}
+
+	i := strings.LastIndex(reference, ":")
+	if i <= strings.LastIndex(reference, "/") || i == len(reference)-1 {
+		return Dependency{}, fmt.Errorf("dependency %q does not specify a version tag", uri)
+	}
+	name, version := reference[:i], reference[i+1:]
+
+	registry, repository, ok := strings.Cut(name, "/")
+	if !ok || !strings.Contains(repository, "/") {
+		return Dependency{}, fmt.Errorf("dependency %q does not include a registry and an image namespace", uri)
+	}
+
+	parts := strings.Split(repository, "/")
+	namespace := parts[len(parts)-2]
+	if namespace == "paketobuildpacks" {
+		namespace = "paketo-buildpacks"
+	}
+
+	return Dependency{
+		ID:         fmt.Sprintf("%s/%s", namespace, parts[len(parts)-1]),
+		Registry:   registry,
+		Repository: repository,
+		Version:    version,
+		URI:        uri,
+	}, nil
+}
+
+// Bump is the semver component that changes between two versions.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseVersion parses a plain major.minor.patch version.
+func ParseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+
+	return parsed, true
+}
+
+// CompareVersions returns -1, 0 or 1 when a is lower than, equal to or higher
+// than b.
+func CompareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// BumpBetween returns the most significant semver component that differs
+// between two versions.
+func BumpBetween(old, new [3]int) Bump {
+	for i, bump := range []Bump{BumpMajor, BumpMinor, BumpPatch} {
+		if old[i] != new[i] {
+			return bump
+		}
+	}
+
+	return BumpNone
+}
+
+// SortedKeys returns the keys of m in lexical order.
+func SortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,133 @@
+package composite_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/nodejs/internal/composite"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestComposite(t *testing.T) {
+	suite := spec.New("composite", spec.Report(report.Terminal{}))
+	suite("Decode", testDecode)
+	suite("ParseDependencyURI", testParseDependencyURI)
+	suite("Versions", testVersions)
+	suite.Run(t)
+}
+
+func testDecode(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("decodes the order groups and dependencies", func() {
+		dir := t.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "buildpack.toml"), []byte(`
+[[order]]
+  [[order.group]]
+    id = "paketo-buildpacks/node-engine"
+    version = "1.0.0"
+
+  [[order.group]]
+    id = "paketo-buildpacks/datadog"
+    version = "2.0.0"
+    optional = true
+`), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "package.toml"), []byte(`
+[[dependencies]]
+  uri = "docker://docker.io/paketobuildpacks/node-engine:1.0.0"
+`), 0644)).To(Succeed())
+
+		buildpack, err := composite.DecodeBuildpack(filepath.Join(dir, "buildpack.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buildpack.Order).To(Equal([]composite.Order{{Group: []composite.Member{
+			{ID: "paketo-buildpacks/node-engine", Version: "1.0.0"},
+			{ID: "paketo-buildpacks/datadog", Version: "2.0.0", Optional: true},
+		}}}))
+
+		pkg, err := composite.DecodePackage(filepath.Join(dir, "package.toml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pkg.Dependencies).To(HaveLen(1))
+		Expect(pkg.Dependencies[0].URI).To(Equal("docker://docker.io/paketobuildpacks/node-engine:1.0.0"))
+	})
+
+	context("when the file is not valid TOML", func() {
+		it("returns an error naming the file", func() {
+			path := filepath.Join(t.TempDir(), "buildpack.toml")
+			Expect(os.WriteFile(path, []byte("[[order]"), 0644)).To(Succeed())
+
+			_, err := composite.DecodeBuildpack(path)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse " + path)))
+		})
+	})
+}
+
+func testParseDependencyURI(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("maps the image onto the id of the buildpack", func() {
+		dependency, err := composite.ParseDependencyURI("docker://docker.io/paketobuildpacks/node-engine:1.2.3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dependency).To(Equal(composite.Dependency{
+			ID:         "paketo-buildpacks/node-engine",
+			Registry:   "docker.io",
+			Repository: "paketobuildpacks/node-engine",
+			Version:    "1.2.3",
+			URI:        "docker://docker.io/paketobuildpacks/node-engine:1.2.3",
+		}))
+	})
+
+	it("keeps namespaces other than paketobuildpacks", func() {
+		dependency, err := composite.ParseDependencyURI("docker://localhost:5000/some-org/some-buildpack:0.1.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dependency.ID).To(Equal("some-org/some-buildpack"))
+		Expect(dependency.Registry).To(Equal("localhost:5000"))
+		Expect(dependency.Version).To(Equal("0.1.0"))
+	})
+
+	context("when the uri is invalid", func() {
+		it("returns an error", func() {
+			_, err := composite.ParseDependencyURI("build/some-buildpack.tgz")
+			Expect(err).To(MatchError(`dependency "build/some-buildpack.tgz" is not a docker:// image reference`))
+
+			_, err = composite.ParseDependencyURI("docker://docker.io/paketobuildpacks/node-engine")
+			Expect(err).To(MatchError(`dependency "docker://docker.io/paketobuildpacks/node-engine" does not specify a version tag`))
+
+			_, err = composite.ParseDependencyURI("docker://node-engine:1.2.3")
+			Expect(err).To(MatchError(ContainSubstring("does not include a registry and an image namespace")))
+		})
+	})
+}
+
+func testVersions(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	it("parses plain major.minor.patch versions only", func() {
+		version, ok := composite.ParseVersion("1.22.333")
+		Expect(ok).To(BeTrue())
+		Expect(version).To(Equal([3]int{1, 22, 333}))
+
+		for _, invalid := range []string{"latest", "1.2", "1.2.3-rc.1", "v1.2.3", "1.-2.3", "1.+2.3"} {
+			_, ok := composite.ParseVersion(invalid)
+			Expect(ok).To(BeFalse(), invalid)
+		}
+	})
+
+	it("compares versions and finds the bump between them", func() {
+		Expect(composite.CompareVersions([3]int{1, 2, 3}, [3]int{1, 10, 0})).To(Equal(-1))
+		Expect(composite.CompareVersions([3]int{2, 0, 0}, [3]int{1, 10, 0})).To(Equal(1))
+		Expect(composite.CompareVersions([3]int{1, 2, 3}, [3]int{1, 2, 3})).To(Equal(0))
+
+		Expect(composite.BumpBetween([3]int{1, 2, 3}, [3]int{1, 2, 4})).To(Equal(composite.BumpPatch))
+		Expect(composite.BumpBetween([3]int{1, 2, 3}, [3]int{1, 3, 0})).To(Equal(composite.BumpMinor))
+		Expect(composite.BumpBetween([3]int{1, 2, 3}, [3]int{2, 0, 0})).To(Equal(composite.BumpMajor))
+		Expect(composite.BumpBetween([3]int{1, 2, 3}, [3]int{1, 2, 3})).To(Equal(composite.BumpNone))
+	})
+
+	it("sorts map keys", func() {
+		Expect(composite.SortedKeys(map[string]int{"b": 1, "a": 2, "c": 3})).To(Equal([]string{"a", "b", "c"}))
+	})
+}
@@ -0,0 +1,195 @@
+#!/bin/bash
+
+set -e
+set -u
+set -o pipefail
+
+readonly ROOT_DIR="$(cd "$(dirname "${0}")/.." && pwd)"
+readonly BIN_DIR="${ROOT_DIR}/.bin"
+readonly BUILD_DIR="${ROOT_DIR}/build"
+
+# shellcheck source=SCRIPTDIR/.util/tools.sh
+source "${ROOT_DIR}/scripts/.util/tools.sh"
+
+# shellcheck source=SCRIPTDIR/.util/print.sh
+source "${ROOT_DIR}/scripts/.util/print.sh"
+
+function main {
+  local version output token workspace
+  token=""
+
+  while [[ "${#}" != 0 ]]; do
+    case "${1}" in
+      --version|-v)
+        version="${2}"
+        shift 2
+        ;;
+
+      --output|-o)
+        output="${2}"
+        shift 2
+        ;;
+
+      --token|-t)
+        token="${2}"
+        shift 2
+        ;;
+
+      --help|-h)
+        shift 1
+        usage
+        exit 0
+        ;;
+
+      "")
+        # skip if the argument is empty
+        shift 1
+        ;;
+
+      *)
+        util::print::error "unknown argument \"${1}\""
+    esac
+  done
+
+  if [[ -z "${version:-}" ]]; then
+    usage
+    echo
+    util::print::error "--version is required"
+  fi
+
+  if [[ -z "${output:-}" ]]; then
+    output="${BUILD_DIR}/buildpackage-offline.cnb"
+  fi
+
+  mkdir -p "${BIN_DIR}" "$(dirname "${output}")"
+  output="$(cd "$(dirname "${output}")" && pwd)/$(basename "${output}")"
+  export PATH="${BIN_DIR}:${PATH}"
+
+  workspace="$(mktemp -d)"
+  trap "rm -rf '${workspace}'" EXIT
+
+  repo::copy "${workspace}"
+
+  # package.sh starts by clearing the build directory of the repository it
+  # belongs to, so it runs in a copy of the repository to leave ${BUILD_DIR}
+  # and any buildpackage that is in use alone.
+  "${workspace}/scripts/package.sh" --version "${version}" --token "${token}"
+
+  tools::install
+  buildpack::offline::components "${workspace}/build/offline"
+  buildpackage::create "${workspace}/build" "${output}"
+}
+
+function usage() {
+  cat <<-USAGE
+package-offline.sh --version <version> [OPTIONS]
+
+Packages the buildpack into a buildpackage .cnb file in which every component
+buildpack includes its dependencies, for builds without network access.
+
+OPTIONS
+  --help               -h            prints the command usage
+  --version <version>  -v <version>  specifies the version number to use when packaging the buildpack
+  --output <output>    -o <output>   location to output the packaged buildpackage artifact (default: ${ROOT_DIR}/build/buildpackage-offline.cnb)
+  --token <token>                    Token used to download assets from GitHub (e.g. jam, pack, etc) (optional)
+USAGE
+}
+
+# Copies what package.sh needs into the given directory, sharing the tools
+# that are already installed in ${BIN_DIR}.
+function repo::copy() {
+  local workspace
+  workspace="${1}"
+
+  util::print::title "Copying repo into ${workspace}..."
+
+  cp "${ROOT_DIR}/buildpack.toml" "${ROOT_DIR}/package.toml" "${workspace}/"
+  cp -R "${ROOT_DIR}/scripts" "${workspace}/scripts"
+  ln -s "${BIN_DIR}" "${workspace}/.bin"
+}
+
+function tools::install() {
+  util::tools::create-package::install \
+    --directory "${BIN_DIR}"
+}
+
+# Packages every component listed in package.toml from its source at the
+# released version, vendoring the dependencies that it would otherwise download
+# at build time. The resulting archives are written to the given directory and
+# are named after the component.
+function buildpack::offline::components() {
+  local dir uri reference name version source
+  dir="${1}"
+
+  util::print::title "Vendoring component buildpacks into ${dir}..."
+
+  mkdir -p "${dir}"
+
+  for uri in $(yj -tj < "${ROOT_DIR}/package.toml" | jq -r '.dependencies[].uri'); do
+    reference="${uri#docker://docker.io/paketobuildpacks/}"
+    name="${reference%%:*}"
+    version="${reference##*:}"
+    source="$(mktemp -d -p "${dir}")"
+
+    util::print::info "Packaging ${name} ${version} with its dependencies..."
+
+    git clone --quiet --depth 1 --branch "v${version}" "https://github.com/paketo-buildpacks/${name}" "${source}"
+
+    if [[ -f "${source}/.libbuildpack" ]]; then
+      create-package \
+        --source "${source}" \
+        --destination "${source}/build" \
+        --version "${version}" \
+        --include-dependencies
+
+      tar -czf "${dir}/${name}.tgz" -C "${source}/build" .
+    else
+      pushd "${source}" > /dev/null
+        ./scripts/build.sh
+      popd > /dev/null
+
+      jam pack \
+        --buildpack "${source}/buildpack.toml" \
+        --version "${version}" \
+        --offline \
+        --output "${dir}/${name}.tgz"
+    fi
+
+    rm -rf "${source}"
+  done
+}
+
+# Packages the release archive that package.sh left in the given build
+# directory against the vendored components. They are only built for the local
+# architecture, so the offline buildpackage drops the targets of package.toml.
+function buildpackage::create() {
+  local build_dir output tmp_dir arch
+  build_dir="${1}"
+  output="${2}"
+
+  util::print::title "Packaging offline buildpack..."
+
+  tmp_dir="$(mktemp -d -p "${build_dir}")"
+  tar -xzf "${build_dir}/buildpack-release-artifact.tgz" -C "${tmp_dir}"
+
+  yj -tj < "${tmp_dir}/package.toml" \
+    | jq --arg dir "${build_dir}/offline" 'del(.targets) | .dependencies |= map(.uri |= ($dir + "/" + (sub("^docker://docker.io/paketobuildpacks/"; "") | sub(":.*$"; "")) + ".tgz"))' \
+    | yj -jt > "${tmp_dir}/package.offline.toml"
+  mv "${tmp_dir}/package.offline.toml" "${tmp_dir}/package.toml"
+
+  arch=$(util::tools::arch)
+
+  pushd "${tmp_dir}" > /dev/null
+    pack \
+      buildpack package "${output}" \
+      --config package.toml \
+      --format file \
+      --target "linux/${arch}"
+  popd > /dev/null
+
+  if [[ -e "${output%.cnb}-linux-${arch}.cnb" ]]; then
+    mv "${output%.cnb}-linux-${arch}.cnb" "${output}"
+  fi
+}
+
+main "${@:-}"
@@ -0,0 +1,65 @@
+#!/bin/bash
+
+set -e
+set -u
+set -o pipefail
+
+readonly ROOT_DIR="$(cd "$(dirname "${0}")/.." && pwd)"
+readonly FIXTURE_DIR="${ROOT_DIR}/integration/testdata/yarn_berry"
+readonly YARN_VERSION="4.5.3"
+
+# shellcheck source=SCRIPTDIR/.util/print.sh
+source "${ROOT_DIR}/scripts/.util/print.sh"
+
+function main {
+  while [[ "${#}" != 0 ]]; do
+    case "${1}" in
+      --help|-h)
+        shift 1
+        usage
+        exit 0
+        ;;
+
+      "")
+        # skip if the argument is empty
+        shift 1
+        ;;
+
+      *)
+        util::print::error "unknown argument \"${1}\""
+    esac
+  done
+
+  fixture::generate
+}
+
+function usage() {
+  cat <<-USAGE
+yarn-berry-fixture.sh [OPTIONS]
+
+Regenerates the committed zero-install artifacts of the yarn_berry integration
+fixture: the Yarn release in .yarn/releases, the .yarn/cache, .pnp.cjs and
+yarn.lock. Run it after changing the dependencies of the fixture and commit
+the result, so that the integration suite can build it without network.
+
+OPTIONS
+  --help  -h  prints the command usage
+USAGE
+}
+
+function fixture::generate() {
+  util::print::title "Installing the yarn_berry fixture with Yarn ${YARN_VERSION}..."
+
+  rm -rf "${FIXTURE_DIR}/.yarn/releases" "${FIXTURE_DIR}/.yarn/cache" "${FIXTURE_DIR}/.pnp.cjs" "${FIXTURE_DIR}/.pnp.loader.mjs" "${FIXTURE_DIR}/yarn.lock"
+
+  docker run --rm \
+    --volume "${FIXTURE_DIR}:/workspace" \
+    --workdir /workspace \
+    --env YARN_ENABLE_NETWORK=true \
+    node:20-slim \
+    sh -c "corepack enable && yarn set version ${YARN_VERSION} --yarn-path && yarn install && chown -R $(id -u):$(id -g) /workspace"
+
+  util::print::success "Generated the zero-install artifacts in ${FIXTURE_DIR}, commit them along with the fixture"
+}
+
+main "${@:-}"